@@ -0,0 +1,265 @@
+package apigee
+
+import (
+  "encoding/json"
+  "errors"
+  "fmt"
+  "path"
+  "strconv"
+  "strings"
+  "time"
+)
+
+// StatsTimeUnits lists the valid time units for grouping analytics results.
+var StatsTimeUnits = []string{"second", "minute", "hour", "day", "week", "month", "quarter", "year"}
+
+// statsTimeFormat is the layout of each end of the timeRange parameter, eg "01/31/2024 23:59".
+const statsTimeFormat = "01/02/2006 15:04"
+
+// AnalyticsService is an interface for interfacing with the Apigee Edge Admin API
+// dealing with analytics statistics for an environment.
+type AnalyticsService interface {
+  Stats(string, *StatsQuery) ([]StatsRow, *Response, error)
+}
+
+type AnalyticsServiceOp struct {
+  client *ApigeeClient
+}
+
+var _ AnalyticsService = &AnalyticsServiceOp{}
+
+// StatsQuery describes a query for analytics statistics. Build one with
+// NewStatsQuery and the chained setters, eg:
+//
+//   q := NewStatsQuery("apiproxy").
+//     Metrics("sum(message_count)", "avg(total_response_time)").
+//     TimeRange(from, to).
+//     TimeUnit("day").
+//     Filter("(response_status_code ge 500)").
+//     Sort("DESC", "sum(message_count)").
+//     TopK(10)
+type StatsQuery struct {
+  dimensions  []string
+  metrics     []string
+  from        time.Time
+  to          time.Time
+  timeUnit    string
+  filter      string
+  sort        string
+  sortBy      string
+  topk        int
+  limit       int
+  offset      int
+}
+
+// The query parameters Edge supports for stats.
+type statsParams struct {
+  Select     string  `url:"select"`
+  TimeRange  string  `url:"timeRange"`
+  TimeUnit   string  `url:"timeUnit,omitempty"`
+  Filter     string  `url:"filter,omitempty"`
+  Sort       string  `url:"sort,omitempty"`
+  SortBy     string  `url:"sortby,omitempty"`
+  TopK       int     `url:"topk,omitempty"`
+  Limit      int     `url:"limit,omitempty"`
+  Offset     int     `url:"offset,omitempty"`
+}
+
+// StatsRow holds the metrics for one dimension value in one environment, for
+// one time interval. Timestamp marks the start of the interval, and is zero
+// when the query has no TimeUnit.
+type StatsRow struct {
+  Env        string
+  Dimension  string
+  Timestamp  Timestamp
+  // metric values keyed by metric, eg "sum(message_count)"
+  Metrics    map[string]float64
+}
+
+// NewStatsQuery returns a query that groups results by the given dimensions,
+// like "apiproxy" or "developer_app".
+func NewStatsQuery(dimensions ...string) *StatsQuery {
+  return &StatsQuery{dimensions: dimensions}
+}
+
+// Metrics adds metrics to retrieve, like "sum(message_count)".
+func (q *StatsQuery) Metrics(metrics ...string) *StatsQuery {
+  q.metrics = append(q.metrics, metrics...)
+  return q
+}
+
+// TimeRange sets the period to report on. Times are converted to UTC.
+func (q *StatsQuery) TimeRange(from, to time.Time) *StatsQuery {
+  q.from, q.to = from, to
+  return q
+}
+
+// TimeUnit groups results into intervals, like "hour" or "day".
+func (q *StatsQuery) TimeUnit(unit string) *StatsQuery {
+  q.timeUnit = unit
+  return q
+}
+
+// Filter restricts results with an expression like "(apiproxy eq 'flights')".
+func (q *StatsQuery) Filter(expression string) *StatsQuery {
+  q.filter = expression
+  return q
+}
+
+// Sort orders results, "ASC" or "DESC", by the given metric.
+func (q *StatsQuery) Sort(order, byMetric string) *StatsQuery {
+  q.sort, q.sortBy = order, byMetric
+  return q
+}
+
+// TopK limits results to the top k dimension values.
+func (q *StatsQuery) TopK(k int) *StatsQuery {
+  q.topk = k
+  return q
+}
+
+// Limit sets the maximum number of results.
+func (q *StatsQuery) Limit(n int) *StatsQuery {
+  q.limit = n
+  return q
+}
+
+// Offset skips results, for paging along with Limit.
+func (q *StatsQuery) Offset(n int) *StatsQuery {
+  q.offset = n
+  return q
+}
+
+// params validates the query and returns its query parameters.
+func (q *StatsQuery) params() (*statsParams, error) {
+  if len(q.dimensions) == 0 {
+    return nil, errors.New("stats query must have at least one dimension")
+  }
+  if len(q.metrics) == 0 {
+    return nil, errors.New("stats query must have at least one metric")
+  }
+  if q.from.IsZero() || q.to.IsZero() || !q.from.Before(q.to) {
+    return nil, errors.New("stats query must have a time range with from before to")
+  }
+  if q.timeUnit != "" && !containsString(StatsTimeUnits, q.timeUnit) {
+    return nil, fmt.Errorf("stats time unit %q must be one of %v", q.timeUnit, StatsTimeUnits)
+  }
+  if q.sort != "" && q.sort != "ASC" && q.sort != "DESC" {
+    return nil, fmt.Errorf("stats sort order %q must be ASC or DESC", q.sort)
+  }
+  if q.topk < 0 || q.limit < 0 || q.offset < 0 {
+    return nil, errors.New("stats topk, limit and offset must not be negative")
+  }
+  return &statsParams{
+    Select: strings.Join(q.metrics, ","),
+    TimeRange: q.from.UTC().Format(statsTimeFormat) + "~" + q.to.UTC().Format(statsTimeFormat),
+    TimeUnit: q.timeUnit,
+    Filter: q.filter,
+    Sort: q.sort,
+    SortBy: q.sortBy,
+    TopK: q.topk,
+    Limit: q.limit,
+    Offset: q.offset,
+  }, nil
+}
+
+// The nested form of a stats response.
+type statsResponse struct {
+  Environments []struct {
+    Name        string  `json:"name"`
+    Dimensions  []struct {
+      Name     string         `json:"name"`
+      Metrics  []statsMetric  `json:"metrics"`
+    } `json:"dimensions"`
+  } `json:"environments"`
+}
+
+type statsMetric struct {
+  Name    string             `json:"name"`
+  Values  []json.RawMessage  `json:"values"`
+}
+
+// parseStatsNumber parses a metric value, which Edge sends as a string like
+// "123.0", or sometimes as a number.
+func parseStatsNumber(raw json.RawMessage) (float64, error) {
+  var f float64
+  if e := json.Unmarshal(raw, &f); e == nil {
+    return f, nil
+  }
+  var s string
+  if e := json.Unmarshal(raw, &s); e != nil {
+    return 0, fmt.Errorf("unexpected metric value %s", raw)
+  }
+  return strconv.ParseFloat(s, 64)
+}
+
+// parseStatsValue parses one element of the values of a metric. With a time
+// unit, each is an object holding a timestamp and a value; without, each is
+// just the value.
+func parseStatsValue(raw json.RawMessage) (Timestamp, float64, error) {
+  var timed struct {
+    Timestamp  *Timestamp       `json:"timestamp"`
+    Value      json.RawMessage  `json:"value"`
+  }
+  if e := json.Unmarshal(raw, &timed); e == nil && timed.Timestamp != nil {
+    f, e := parseStatsNumber(timed.Value)
+    return *timed.Timestamp, f, e
+  }
+  f, e := parseStatsNumber(raw)
+  return Timestamp{}, f, e
+}
+
+// flatten converts the nested response into rows, one per environment,
+// dimension value and time interval, in the order Edge returned them.
+func (r statsResponse) flatten() ([]StatsRow, error) {
+  rows := []StatsRow{}
+  for _, env := range r.Environments {
+    for _, dim := range env.Dimensions {
+      index := map[int64]int{}
+      for _, metric := range dim.Metrics {
+        for _, raw := range metric.Values {
+          ts, value, e := parseStatsValue(raw)
+          if e != nil {
+            return nil, fmt.Errorf("metric %s of %s: %v", metric.Name, dim.Name, e)
+          }
+          key := ts.Time.UnixNano()
+          i, ok := index[key]
+          if !ok {
+            i = len(rows)
+            index[key] = i
+            rows = append(rows, StatsRow{Env: env.Name, Dimension: dim.Name, Timestamp: ts, Metrics: map[string]float64{}})
+          }
+          rows[i].Metrics[metric.Name] = value
+        }
+      }
+    }
+  }
+  return rows, nil
+}
+
+// Stats runs a stats query against an environment, and returns the results
+// as flat rows.
+func (s *AnalyticsServiceOp) Stats(env string, q *StatsQuery) ([]StatsRow, *Response, error) {
+  if q == nil {
+    return nil, nil, errors.New("must specify a stats query")
+  }
+  params, e := q.params()
+  if e != nil {
+    return nil, nil, e
+  }
+  p, e := addOptions(path.Join("e", env, "stats", strings.Join(q.dimensions, ",")), params)
+  if e != nil {
+    return nil, nil, e
+  }
+  req, e := s.client.NewRequest("GET", p, nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  stats := statsResponse{}
+  resp, e := s.client.Do(req, &stats)
+  if e != nil {
+    return nil, resp, e
+  }
+  rows, e := stats.flatten()
+  return rows, resp, e
+}
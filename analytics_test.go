@@ -0,0 +1,205 @@
+package apigee
+
+import (
+  "encoding/json"
+  "fmt"
+  "net/http"
+  "net/url"
+  "reflect"
+  "strings"
+  "testing"
+  "time"
+)
+
+const (
+  statsTimedJson1 = `{
+  "environments" : [ {
+    "dimensions" : [ {
+      "metrics" : [ {
+        "name" : "sum(message_count)",
+        "values" : [ {
+          "timestamp" : 1704067200000,
+          "value" : "120.0"
+        }, {
+          "timestamp" : 1704153600000,
+          "value" : "80.0"
+        } ]
+      }, {
+        "name" : "avg(total_response_time)",
+        "values" : [ {
+          "timestamp" : 1704067200000,
+          "value" : "35.5"
+        }, {
+          "timestamp" : 1704153600000,
+          "value" : "41.25"
+        } ]
+      } ],
+      "name" : "flights"
+    }, {
+      "metrics" : [ {
+        "name" : "sum(message_count)",
+        "values" : [ {
+          "timestamp" : 1704067200000,
+          "value" : "7.0"
+        } ]
+      } ],
+      "name" : "hotels"
+    } ],
+    "name" : "test"
+  } ],
+  "metaData" : { "errors" : [ ], "notices" : [ ] }
+}`
+
+  statsUntimedJson1 = `{
+  "environments" : [ {
+    "dimensions" : [ {
+      "metrics" : [ { "name" : "sum(message_count)", "values" : [ "200.0" ] } ],
+      "name" : "flights"
+    } ],
+    "name" : "prod"
+  } ]
+}`
+)
+
+func TestStatsResponse_Flatten(t *testing.T) {
+  var stats statsResponse
+  e := json.Unmarshal([]byte(statsTimedJson1), &stats)
+  if e != nil {
+    t.Errorf("while unmarshaling, error:\n%#v\n", e)
+    return
+  }
+  rows, e := stats.flatten()
+  if e != nil {
+    t.Errorf("while flattening, error: %v", e)
+    return
+  }
+  if len(rows) != 3 {
+    t.Errorf("got=%#v", rows)
+    return
+  }
+  first := rows[0]
+  if first.Env != "test" || first.Dimension != "flights" || first.Timestamp.String() != "1704067200000" ||
+    first.Metrics["sum(message_count)"] != 120 || first.Metrics["avg(total_response_time)"] != 35.5 {
+    t.Errorf("first: got=%#v", first)
+  }
+  if rows[1].Timestamp.String() != "1704153600000" || rows[1].Metrics["avg(total_response_time)"] != 41.25 {
+    t.Errorf("second: got=%#v", rows[1])
+  }
+  if rows[2].Dimension != "hotels" || rows[2].Metrics["sum(message_count)"] != 7 {
+    t.Errorf("third: got=%#v", rows[2])
+  }
+
+  e = json.Unmarshal([]byte(statsUntimedJson1), &stats)
+  if e != nil {
+    t.Errorf("while unmarshaling, error:\n%#v\n", e)
+    return
+  }
+  rows, e = stats.flatten()
+  if e != nil {
+    t.Errorf("while flattening, error: %v", e)
+    return
+  }
+  if len(rows) != 1 || rows[0].Env != "prod" || !rows[0].Timestamp.IsZero() || rows[0].Metrics["sum(message_count)"] != 200 {
+    t.Errorf("untimed: got=%#v", rows)
+  }
+}
+
+func TestStatsQuery_Params(t *testing.T) {
+  from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+  to := time.Date(2024, 1, 31, 23, 59, 0, 0, time.UTC)
+  q := NewStatsQuery("apiproxy").
+    Metrics("sum(message_count)", "avg(total_response_time)").
+    TimeRange(from, to).
+    TimeUnit("day").
+    Filter("(response_status_code ge 500)").
+    Sort("DESC", "sum(message_count)").
+    TopK(5).
+    Limit(100).
+    Offset(10)
+  params, e := q.params()
+  if e != nil {
+    t.Errorf("while building params, error: %v", e)
+    return
+  }
+  got, e := addOptions("e/test/stats/apiproxy", params)
+  if e != nil {
+    t.Errorf("while adding options, error: %v", e)
+    return
+  }
+  expected := "e/test/stats/apiproxy?filter=%28response_status_code+ge+500%29&limit=100&offset=10" +
+    "&select=sum%28message_count%29%2Cavg%28total_response_time%29&sort=DESC&sortby=sum%28message_count%29" +
+    "&timeRange=01%2F01%2F2024+00%3A00~01%2F31%2F2024+23%3A59&timeUnit=day&topk=5"
+  if got != expected {
+    t.Errorf("got=%q\nexpected=%q", got, expected)
+  }
+}
+
+func TestStatsQuery_Invalid(t *testing.T) {
+  from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+  to := from.Add(24 * time.Hour)
+  testCases := []struct {
+    query   *StatsQuery
+    errText string
+  }{
+    {NewStatsQuery().Metrics("sum(message_count)").TimeRange(from, to), "dimension"},
+    {NewStatsQuery("apiproxy").TimeRange(from, to), "metric"},
+    {NewStatsQuery("apiproxy").Metrics("sum(message_count)"), "time range"},
+    {NewStatsQuery("apiproxy").Metrics("sum(message_count)").TimeRange(to, from), "time range"},
+    {NewStatsQuery("apiproxy").Metrics("sum(message_count)").TimeRange(from, to).TimeUnit("fortnight"), "time unit"},
+    {NewStatsQuery("apiproxy").Metrics("sum(message_count)").TimeRange(from, to).Sort("UP", "x"), "sort order"},
+    {NewStatsQuery("apiproxy").Metrics("sum(message_count)").TimeRange(from, to).Limit(-1), "negative"},
+  }
+  for i, tc := range testCases {
+    _, e := tc.query.params()
+    if e == nil || !strings.Contains(e.Error(), tc.errText) {
+      t.Errorf("case %d: got=%v, expected error containing %q", i, e, tc.errText)
+    }
+  }
+}
+
+func TestStats(t *testing.T) {
+  var gotPath string
+  var gotQuery url.Values
+  client, stop := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+    gotPath, gotQuery = r.URL.Path, r.URL.Query()
+    fmt.Fprint(w, statsTimedJson1)
+  })
+  defer stop()
+  from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+  to := time.Date(2024, 1, 2, 23, 59, 0, 0, time.UTC)
+  q := NewStatsQuery("apiproxy", "developer_app").
+    Metrics("sum(message_count)", "avg(total_response_time)").
+    TimeRange(from, to).
+    TimeUnit("day").
+    Sort("DESC", "sum(message_count)")
+  rows, _, e := client.Analytics.Stats("test", q)
+  if e != nil {
+    t.Errorf("unexpected error: %v", e)
+    return
+  }
+  if gotPath != "/v1/o/org1/e/test/stats/apiproxy,developer_app" {
+    t.Errorf("path: got=%q", gotPath)
+  }
+  expectedQuery := url.Values{
+    "select": {"sum(message_count),avg(total_response_time)"},
+    "timeRange": {"01/01/2024 00:00~01/02/2024 23:59"},
+    "timeUnit": {"day"},
+    "sort": {"DESC"},
+    "sortby": {"sum(message_count)"},
+  }
+  if !reflect.DeepEqual(gotQuery, expectedQuery) {
+    t.Errorf("query: got=%v\nexpected=%v", gotQuery, expectedQuery)
+  }
+  if len(rows) != 3 {
+    t.Errorf("rows: got=%#v", rows)
+    return
+  }
+  first := rows[0]
+  if first.Env != "test" || first.Dimension != "flights" || !first.Timestamp.Time.Equal(from) ||
+    first.Metrics["sum(message_count)"] != 120 || first.Metrics["avg(total_response_time)"] != 35.5 {
+    t.Errorf("row 0: got=%#v", first)
+  }
+  if rows[2].Dimension != "hotels" || rows[2].Metrics["sum(message_count)"] != 7 {
+    t.Errorf("row 2: got=%#v", rows[2])
+  }
+}
@@ -3,6 +3,7 @@ package apigee
 
 import (
   "bytes"
+  "context"
   "encoding/json"
   "fmt"
   "os"
@@ -41,6 +42,9 @@ type ApigeeClient struct {
   auth *AdminAuth
   debug bool
 
+  // revisions deployed through this client, used by Rollback
+  history *deploymentHistory
+
   // Base URL for API requests.
   BaseURL *url.URL
 
@@ -55,6 +59,16 @@ type ApigeeClient struct {
   Environments     EnvironmentsService
   Organization     OrganizationService
   Caches           CachesService
+  TargetServers    TargetServersService
+  KeyValueMaps     KeyValueMapsService
+  VirtualHosts     VirtualHostsService
+  Keystores        KeystoresService
+  References       ReferencesService
+  FlowHooks        FlowHooksService
+  ResourceFiles    ResourceFilesService
+  Companies        CompaniesService
+  Apps             AppsService
+  Analytics        AnalyticsService
 	Options          ApigeeClientOptions
 
   // Account           AccountService
@@ -142,6 +156,11 @@ type ApigeeClientOptions struct {
 
   // Optional. Warning: if set to true, HTTP Basic Auth base64 blobs will appear in output.
   Debug bool
+
+  // Optional. What to do when deploying an API Proxy revision would collide with
+  // the basepath and virtual host of another deployed API Proxy. By default,
+  // the client does not check, and Deploy makes no extra requests.
+  BasePathConflicts ConflictPolicy
 }
 
 // AdminAuth holds information about how to authenticate to the Edge Management server.
@@ -193,7 +212,7 @@ func NewApigeeClient(o *ApigeeClientOptions) (*ApigeeClient,error) {
   }
   baseURL.Path = path.Join(baseURL.Path, "v1/o/", o.Org, "/")
 
-  c := &ApigeeClient{client: httpClient, BaseURL: baseURL, UserAgent: userAgent}
+  c := &ApigeeClient{client: httpClient, BaseURL: baseURL, UserAgent: userAgent, history: newDeploymentHistory()}
   c.SharedFlows = &SharedFlowsServiceOp{client: c}
   c.Proxies = &ProxiesServiceOp{client: c}
   c.Products = &ProductsServiceOp{client: c}
@@ -201,6 +220,16 @@ func NewApigeeClient(o *ApigeeClientOptions) (*ApigeeClient,error) {
   c.Environments = &EnvironmentsServiceOp{client: c}
   c.Organization = &OrganizationServiceOp{client: c}
   c.Caches = &CachesServiceOp{client: c}
+  c.TargetServers = &TargetServersServiceOp{client: c}
+  c.KeyValueMaps = &KeyValueMapsServiceOp{client: c}
+  c.VirtualHosts = &VirtualHostsServiceOp{client: c}
+  c.Keystores = &KeystoresServiceOp{client: c}
+  c.References = &ReferencesServiceOp{client: c}
+  c.FlowHooks = &FlowHooksServiceOp{client: c}
+  c.ResourceFiles = &ResourceFilesServiceOp{client: c}
+  c.Companies = &CompaniesServiceOp{client: c}
+  c.Apps = &AppsServiceOp{client: c}
+  c.Analytics = &AnalyticsServiceOp{client: c}
   c.Options = *o;
 
   var e error = nil
@@ -219,7 +248,7 @@ func NewApigeeClient(o *ApigeeClientOptions) (*ApigeeClient,error) {
   if o.Debug {
     c.debug = true
     c.onRequestCompleted = func(req *http.Request, resp *http.Response)  {
-      debugDump(httputil.DumpResponse(resp, true))
+      debugDump(httputil.DumpResponse(resp, !isSensitive(req)))
     }
   }
 
@@ -282,6 +311,8 @@ func (c *ApigeeClient) NewRequest(method, urlStr string, body interface{}) (*htt
   //
   // c.BaseURL = u
   u.Path = path.Join(c.BaseURL.Path, rel.Path)
+  // keep any escaped characters, like %2F, in the path as given
+  u.RawPath = path.Join(c.BaseURL.EscapedPath(), rel.EscapedPath())
 
   if c.debug {
 		fmt.Printf("u: %#v\n", u)
@@ -333,6 +364,21 @@ func newResponse(r *http.Response) *Response {
 }
 
 
+// sensitiveKey is the context key marking a request whose body, and the body
+// of its response, must not appear in debug output.
+type sensitiveKey struct{}
+
+// markSensitive returns a copy of the request marked so that debug output
+// omits the request and response bodies.
+func markSensitive(req *http.Request) *http.Request {
+  return req.WithContext(context.WithValue(req.Context(), sensitiveKey{}, true))
+}
+
+func isSensitive(req *http.Request) bool {
+  sensitive, _ := req.Context().Value(sensitiveKey{}).(bool)
+  return sensitive
+}
+
 func debugDump(data []byte, err error) {
     if err == nil {
         fmt.Printf("%s\n\n", data)
@@ -347,7 +393,7 @@ func debugDump(data []byte, err error) {
 // raw response will be written to v, without attempting to decode it.
 func (c *ApigeeClient) Do(req *http.Request, v interface{}) (*Response, error) {
   if c.debug {
-    debugDump(httputil.DumpRequestOut(req, true))
+    debugDump(httputil.DumpRequestOut(req, !isSensitive(req)))
   }
 
   resp, e := c.client.Do(req)
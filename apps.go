@@ -0,0 +1,231 @@
+package apigee
+
+import (
+  "encoding/json"
+  "errors"
+  "fmt"
+  "path"
+)
+
+const appsPath = "apps"
+
+// appsPageSize is the number of apps requested per page when LookupKey scans
+// all the apps in the organization.
+var appsPageSize = 1000
+
+// AppsService is an interface for interfacing with the Apigee Edge Admin API
+// dealing with all the apps in an organization, whether they belong to a
+// developer or a company.
+type AppsService interface {
+  List(*AppListOptions) ([]DeveloperApp, *Response, error)
+  Get(string) (*DeveloperApp, *Response, error)
+  LookupKey(string) (*KeyResolution, error)
+}
+
+type AppsServiceOp struct {
+  client *ApigeeClient
+}
+
+var _ AppsService = &AppsServiceOp{}
+
+// AppListOptions holds optional parameters for listing the apps in an organization.
+type AppListOptions struct {
+  // "approved" or "revoked"
+  Status              string  `url:"status,omitempty"`
+  // "developer" or "company"
+  AppType             string  `url:"apptype,omitempty"`
+  // return the full apps, rather than only their ids
+  Expand              bool    `url:"expand,omitempty"`
+  // with Expand, also return the credentials of each app
+  IncludeCredentials  bool    `url:"includeCred,omitempty"`
+  // the maximum number of apps to return
+  Rows                int     `url:"rows,omitempty"`
+  // the app id to start from; this app is included in the results
+  StartKey            string  `url:"startKey,omitempty"`
+}
+
+// KeyResolution describes a consumer key, the app it belongs to, and the
+// developer or company that owns the app.
+type KeyResolution struct {
+  ConsumerKey  string
+  KeyStatus    string
+  ExpiresAt    Timestamp
+  Products     []CredentialProduct
+  AppId        string
+  AppName      string
+  AppStatus    string
+  // "developer" or "company"
+  OwnerType    string
+  // the email of the developer, or the name of the company
+  Owner        string
+  DeveloperId  string
+}
+
+// decodeAppList decodes the response to a list of apps, which is an array of
+// app ids, or, when expanded, an object holding an array of apps.
+func decodeAppList(raw json.RawMessage) ([]DeveloperApp, error) {
+  var ids []string
+  if e := json.Unmarshal(raw, &ids); e == nil {
+    apps := make([]DeveloperApp, 0, len(ids))
+    for _, id := range ids {
+      apps = append(apps, DeveloperApp{Id: id})
+    }
+    return apps, nil
+  }
+  root := developerAppsRoot{}
+  e := json.Unmarshal(raw, &root)
+  if e != nil {
+    return nil, e
+  }
+  return root.Apps, nil
+}
+
+// List retrieves the apps in the organization. Unless opt.Expand is true, only
+// the Id of each returned app is set.
+func (s *AppsServiceOp) List(opt *AppListOptions) ([]DeveloperApp, *Response, error) {
+  p, e := addOptions(appsPath, opt)
+  if e != nil {
+    return nil, nil, e
+  }
+  req, e := s.client.NewRequest("GET", p, nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  if opt != nil && opt.IncludeCredentials {
+    req = markSensitive(req)
+  }
+  raw := json.RawMessage{}
+  resp, e := s.client.Do(req, &raw)
+  if e != nil {
+    return nil, resp, e
+  }
+  apps, e := decodeAppList(raw)
+  return apps, resp, e
+}
+
+// Get retrieves an app by its id.
+func (s *AppsServiceOp) Get(appId string) (*DeveloperApp, *Response, error) {
+  req, e := s.client.NewRequest("GET", path.Join(appsPath, appId), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  req = markSensitive(req)
+  returnedApp := DeveloperApp{}
+  resp, e := s.client.Do(req, &returnedApp)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedApp, resp, e
+}
+
+// findAppByKey returns the app holding the consumer key, or nil.
+func findAppByKey(apps []DeveloperApp, consumerKey string) *DeveloperApp {
+  for i := range apps {
+    if findCredential(apps[i].Credentials, consumerKey) != nil {
+      return &apps[i]
+    }
+  }
+  return nil
+}
+
+// scanForKey pages through all the apps in the organization, with their
+// credentials, until it finds the app holding the consumer key.
+func (s *AppsServiceOp) scanForKey(consumerKey string) (*DeveloperApp, error) {
+  opt := &AppListOptions{Expand: true, IncludeCredentials: true, Rows: appsPageSize}
+  for {
+    apps, _, e := s.List(opt)
+    if e != nil {
+      return nil, e
+    }
+    if app := findAppByKey(apps, consumerKey); app != nil {
+      return app, nil
+    }
+    if len(apps) < appsPageSize {
+      return nil, nil
+    }
+    // the next page starts with the last app of this one
+    opt.StartKey = apps[len(apps)-1].Id
+  }
+}
+
+// listAll pages through all the apps in the organization, with their
+// credentials.
+func (s *AppsServiceOp) listAll() ([]DeveloperApp, error) {
+  opt := &AppListOptions{Expand: true, IncludeCredentials: true, Rows: appsPageSize}
+  all := []DeveloperApp{}
+  for {
+    apps, _, e := s.List(opt)
+    if e != nil {
+      return nil, e
+    }
+    page := apps
+    if opt.StartKey != "" && len(page) > 0 && page[0].Id == opt.StartKey {
+      // the start key was the last app of the previous page
+      page = page[1:]
+    }
+    all = append(all, page...)
+    if len(page) == 0 || len(apps) < appsPageSize {
+      return all, nil
+    }
+    opt.StartKey = apps[len(apps)-1].Id
+  }
+}
+
+// resolveKey builds the resolution of a consumer key held by the app.
+func resolveKey(app *DeveloperApp, cred *Credential) *KeyResolution {
+  resolution := &KeyResolution{
+    ConsumerKey: cred.ConsumerKey,
+    KeyStatus: cred.Status,
+    ExpiresAt: cred.ExpiresAt,
+    Products: cred.ApiProducts,
+    AppId: app.Id,
+    AppName: app.Name,
+    AppStatus: app.Status,
+    DeveloperId: app.DeveloperId,
+  }
+  if app.CompanyName != "" {
+    resolution.OwnerType = "company"
+    resolution.Owner = app.CompanyName
+  } else {
+    resolution.OwnerType = "developer"
+  }
+  return resolution
+}
+
+// LookupKey finds the app that holds a consumer key, and the developer or
+// company that owns the app. Edge has no index by key, so this scans the apps
+// in the organization, then reads the key through its owner to get its current
+// status and the status of each of its API Products.
+func (s *AppsServiceOp) LookupKey(consumerKey string) (*KeyResolution, error) {
+  if consumerKey == "" {
+    return nil, errors.New("must specify the consumer key to look up")
+  }
+  app, e := s.scanForKey(consumerKey)
+  if e != nil {
+    return nil, e
+  }
+  if app == nil {
+    return nil, fmt.Errorf("no app holds the consumer key %s", consumerKey)
+  }
+  resolution := resolveKey(app, findCredential(app.Credentials, consumerKey))
+
+  var owned *DeveloperAppsServiceOp
+  if resolution.OwnerType == "company" {
+    owned = &DeveloperAppsServiceOp{client: s.client, companyName: app.CompanyName}
+  } else {
+    developer, _, e := s.client.Developers.Get(app.DeveloperId)
+    if e != nil {
+      return resolution, e
+    }
+    resolution.Owner = developer.Email
+    owned = &DeveloperAppsServiceOp{client: s.client, developerId: developer.Email}
+  }
+  cred, _, e := owned.GetKey(app.Name, consumerKey)
+  if e != nil {
+    return resolution, e
+  }
+  resolution.KeyStatus = cred.Status
+  resolution.ExpiresAt = cred.ExpiresAt
+  resolution.Products = cred.ApiProducts
+  return resolution, nil
+}
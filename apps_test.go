@@ -0,0 +1,101 @@
+package apigee
+
+import (
+  "encoding/json"
+  "testing"
+)
+
+const (
+  appIdsJson1 = `[ "0f5b5f1c-0001", "0f5b5f1c-0002" ]`
+
+  expandedOrgAppsJson1 = `{
+  "app" : [ {
+    "appId" : "0f5b5f1c-0001",
+    "name" : "flights-app",
+    "developerId" : "dev1",
+    "status" : "approved",
+    "credentials" : [ {
+      "apiProducts" : [ { "apiproduct" : "flights-basic", "status" : "approved" } ],
+      "consumerKey" : "key1",
+      "expiresAt" : -1,
+      "status" : "approved"
+    } ]
+  }, {
+    "appId" : "0f5b5f1c-0002",
+    "name" : "partner-app",
+    "companyName" : "acme",
+    "status" : "approved",
+    "credentials" : [ {
+      "apiProducts" : [ { "apiproduct" : "partner", "status" : "pending" } ],
+      "consumerKey" : "key2",
+      "expiresAt" : 1924992000000,
+      "status" : "revoked"
+    } ]
+  } ]
+}`
+)
+
+func TestDecodeAppList(t *testing.T) {
+  ids, e := decodeAppList(json.RawMessage(appIdsJson1))
+  if e != nil {
+    t.Errorf("while decoding ids, error:\n%#v\n", e)
+    return
+  }
+  if len(ids) != 2 || ids[0].Id != "0f5b5f1c-0001" || ids[0].Name != "" {
+    t.Errorf("ids: got=%#v", ids)
+  }
+  apps, e := decodeAppList(json.RawMessage(expandedOrgAppsJson1))
+  if e != nil {
+    t.Errorf("while decoding apps, error:\n%#v\n", e)
+    return
+  }
+  if len(apps) != 2 || apps[1].Name != "partner-app" || apps[1].CompanyName != "acme" {
+    t.Errorf("apps: got=%#v", apps)
+  }
+}
+
+func TestResolveKey(t *testing.T) {
+  apps, e := decodeAppList(json.RawMessage(expandedOrgAppsJson1))
+  if e != nil {
+    t.Errorf("while decoding apps, error:\n%#v\n", e)
+    return
+  }
+  testCases := []struct {
+    key       string
+    appName   string
+    ownerType string
+    owner     string
+    status    string
+    product   CredentialProduct
+  }{
+    {"key1", "flights-app", "developer", "", "approved", CredentialProduct{"flights-basic", "approved"}},
+    {"key2", "partner-app", "company", "acme", "revoked", CredentialProduct{"partner", "pending"}},
+  }
+  for i, tc := range testCases {
+    app := findAppByKey(apps, tc.key)
+    if app == nil {
+      t.Errorf("case %d: no app found", i)
+      continue
+    }
+    got := resolveKey(app, findCredential(app.Credentials, tc.key))
+    if got.AppName != tc.appName || got.OwnerType != tc.ownerType || got.Owner != tc.owner ||
+      got.KeyStatus != tc.status || len(got.Products) != 1 || got.Products[0] != tc.product {
+      t.Errorf("case %d: got=%#v", i, got)
+    }
+  }
+  if app := findAppByKey(apps, "key3"); app != nil {
+    t.Errorf("expected no app, got=%#v", app)
+  }
+}
+
+func TestAppListOptions(t *testing.T) {
+  got, e := addOptions("apps", &AppListOptions{Status: "approved", AppType: "company", Expand: true, Rows: 10, StartKey: "abc"})
+  if e != nil {
+    t.Errorf("while adding options, error:\n%#v\n", e)
+    return
+  }
+  expected := "apps?apptype=company&expand=true&rows=10&startKey=abc&status=approved"
+  if got != expected {
+    t.Errorf("got=%q, expected=%q", got, expected)
+  }
+}
@@ -0,0 +1,289 @@
+package apigee
+
+import (
+  "encoding/json"
+  "errors"
+  "fmt"
+  "reflect"
+  "strconv"
+  "strings"
+  "time"
+)
+
+// AttributeError describes a custom attribute that could not be converted to
+// or from a field of a struct.
+type AttributeError struct {
+  Attribute  string
+  Field      string
+  Value      string
+  Err        error
+}
+
+func (e *AttributeError) Error() string {
+  if e.Value != "" {
+    return fmt.Sprintf("attribute %q (field %s): cannot convert %q: %v", e.Attribute, e.Field, e.Value, e.Err)
+  }
+  return fmt.Sprintf("attribute %q (field %s): %v", e.Attribute, e.Field, e.Err)
+}
+
+var (
+  timespanType  = reflect.TypeOf(Timespan{})
+  timestampType = reflect.TypeOf(Timestamp{})
+)
+
+// attributeTag holds the parsed form of a struct tag like `apigee:"name,json,omitempty"`.
+type attributeTag struct {
+  name       string
+  json       bool
+  omitempty  bool
+}
+
+func parseAttributeTag(f reflect.StructField) (attributeTag, bool) {
+  tag, ok := f.Tag.Lookup("apigee")
+  if !ok || tag == "-" {
+    return attributeTag{}, false
+  }
+  parts := strings.Split(tag, ",")
+  t := attributeTag{name: parts[0]}
+  if t.name == "" {
+    t.name = f.Name
+  }
+  for _, opt := range parts[1:] {
+    switch opt {
+    case "json":
+      t.json = true
+    case "omitempty":
+      t.omitempty = true
+    }
+  }
+  return t, true
+}
+
+// ParseTimespan parses a timespan like "30d", "12h" or "90m", or a number of
+// milliseconds like "3600000". Unlike NewTimespan, it reports bad input.
+func ParseTimespan(s string) (*Timespan, error) {
+  s = strings.ToLower(strings.TrimSpace(s))
+  if ms, e := strconv.ParseInt(s, 10, 64); e == nil {
+    return &Timespan{time.Duration(ms) * time.Millisecond}, nil
+  }
+  if strings.HasSuffix(s, "d") {
+    days, e := strconv.Atoi(strings.TrimSuffix(s, "d"))
+    if e != nil {
+      return nil, fmt.Errorf("invalid timespan %q", s)
+    }
+    return &Timespan{time.Duration(days) * 24 * time.Hour}, nil
+  }
+  d, e := time.ParseDuration(s)
+  if e != nil {
+    return nil, fmt.Errorf("invalid timespan %q", s)
+  }
+  return &Timespan{d}, nil
+}
+
+func formatTimespan(span Timespan) string {
+  day := 24 * time.Hour
+  if span.Duration != 0 && span.Duration % day == 0 {
+    return fmt.Sprintf("%dd", span.Duration / day)
+  }
+  return span.Duration.String()
+}
+
+// parseTimestamp parses milliseconds since the epoch, or an RFC3339 time.
+func parseTimestamp(s string) (Timestamp, error) {
+  if ms, e := strconv.ParseInt(s, 10, 64); e == nil {
+    return Timestamp{time.Unix(ms/1000, (ms%1000)*1000000)}, nil
+  }
+  t, e := time.Parse(time.RFC3339, s)
+  if e != nil {
+    return Timestamp{}, errors.New("not milliseconds since the epoch, nor an RFC3339 time")
+  }
+  return Timestamp{t}, nil
+}
+
+func setFieldFromString(v reflect.Value, s string, asJson bool) error {
+  if asJson {
+    return json.Unmarshal([]byte(s), v.Addr().Interface())
+  }
+  switch v.Type() {
+  case timespanType:
+    span, e := ParseTimespan(s)
+    if e != nil {
+      return e
+    }
+    v.Set(reflect.ValueOf(*span))
+    return nil
+  case timestampType:
+    ts, e := parseTimestamp(s)
+    if e != nil {
+      return e
+    }
+    v.Set(reflect.ValueOf(ts))
+    return nil
+  }
+  switch v.Kind() {
+  case reflect.String:
+    v.SetString(s)
+  case reflect.Bool:
+    b, e := strconv.ParseBool(s)
+    if e != nil {
+      return errors.New("not a boolean")
+    }
+    v.SetBool(b)
+  case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+    n, e := strconv.ParseInt(s, 10, v.Type().Bits())
+    if e != nil {
+      return fmt.Errorf("not an integer that fits in %s", v.Type())
+    }
+    v.SetInt(n)
+  case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+    n, e := strconv.ParseUint(s, 10, v.Type().Bits())
+    if e != nil {
+      return fmt.Errorf("not an unsigned integer that fits in %s", v.Type())
+    }
+    v.SetUint(n)
+  case reflect.Float32, reflect.Float64:
+    f, e := strconv.ParseFloat(s, v.Type().Bits())
+    if e != nil {
+      return errors.New("not a number")
+    }
+    v.SetFloat(f)
+  case reflect.Ptr:
+    elem := reflect.New(v.Type().Elem())
+    e := setFieldFromString(elem.Elem(), s, false)
+    if e != nil {
+      return e
+    }
+    v.Set(elem)
+  default:
+    return fmt.Errorf("unsupported field type %s; use the json tag option", v.Type())
+  }
+  return nil
+}
+
+func formatField(v reflect.Value, asJson bool) (string, error) {
+  if asJson {
+    data, e := json.Marshal(v.Interface())
+    return string(data), e
+  }
+  switch v.Type() {
+  case timespanType:
+    return formatTimespan(v.Interface().(Timespan)), nil
+  case timestampType:
+    return v.Interface().(Timestamp).String(), nil
+  }
+  switch v.Kind() {
+  case reflect.String:
+    return v.String(), nil
+  case reflect.Bool:
+    return strconv.FormatBool(v.Bool()), nil
+  case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+    return strconv.FormatInt(v.Int(), 10), nil
+  case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+    return strconv.FormatUint(v.Uint(), 10), nil
+  case reflect.Float32, reflect.Float64:
+    return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+  case reflect.Ptr:
+    return formatField(v.Elem(), false)
+  }
+  return "", fmt.Errorf("unsupported field type %s; use the json tag option", v.Type())
+}
+
+func isEmptyField(v reflect.Value) bool {
+  switch v.Kind() {
+  case reflect.Ptr, reflect.Interface:
+    return v.IsNil()
+  case reflect.Map, reflect.Slice:
+    return v.Len() == 0
+  }
+  return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+}
+
+// isZeroTimestamp reports whether v is a zero Timestamp, or a pointer to one.
+func isZeroTimestamp(v reflect.Value) bool {
+  if v.Kind() == reflect.Ptr {
+    if v.IsNil() {
+      return false
+    }
+    v = v.Elem()
+  }
+  return v.Type() == timestampType && v.Interface().(Timestamp).IsZero()
+}
+
+// Decode sets the fields of the struct pointed to by target from the
+// attributes, using struct tags like `apigee:"tier"` to name the attribute for
+// each field. Fields without the tag, and fields whose attribute is absent, are
+// left unchanged. Supported field types are strings, integers, floats, bools,
+// Timespan (like "30d" or "90m"), Timestamp (milliseconds since the epoch, or
+// RFC3339), and pointers to these. Add the json option, as in
+// `apigee:"limits,json"`, to decode an attribute holding JSON into any type.
+// The first conversion that fails is returned as an *AttributeError.
+func (attrs Attributes) Decode(target interface{}) error {
+  v := reflect.ValueOf(target)
+  if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+    return errors.New("Decode requires a non-nil pointer to a struct")
+  }
+  v = v.Elem()
+  t := v.Type()
+  for i := 0; i < t.NumField(); i++ {
+    f := t.Field(i)
+    tag, ok := parseAttributeTag(f)
+    if !ok {
+      continue
+    }
+    s, present := attrs[tag.name]
+    if !present {
+      continue
+    }
+    if f.PkgPath != "" {
+      return &AttributeError{Attribute: tag.name, Field: f.Name, Err: errors.New("field is not exported")}
+    }
+    e := setFieldFromString(v.Field(i), s, tag.json)
+    if e != nil {
+      return &AttributeError{Attribute: tag.name, Field: f.Name, Value: s, Err: e}
+    }
+  }
+  return nil
+}
+
+// AttributesFrom builds attributes from the tagged fields of a struct, or a
+// pointer to one, converting each field the same way Decode parses it. Fields
+// tagged with the omitempty option are skipped when they hold the zero value.
+// Nil pointers and zero Timestamps, which have no attribute form, are always
+// skipped.
+func AttributesFrom(source interface{}) (Attributes, error) {
+  v := reflect.ValueOf(source)
+  if v.Kind() == reflect.Ptr && !v.IsNil() {
+    v = v.Elem()
+  }
+  if v.Kind() != reflect.Struct {
+    return nil, errors.New("AttributesFrom requires a struct or a pointer to one")
+  }
+  t := v.Type()
+  attrs := Attributes{}
+  for i := 0; i < t.NumField(); i++ {
+    f := t.Field(i)
+    tag, ok := parseAttributeTag(f)
+    if !ok {
+      continue
+    }
+    if f.PkgPath != "" {
+      return nil, &AttributeError{Attribute: tag.name, Field: f.Name, Err: errors.New("field is not exported")}
+    }
+    fv := v.Field(i)
+    if tag.omitempty && isEmptyField(fv) {
+      continue
+    }
+    if fv.Kind() == reflect.Ptr && fv.IsNil() && !tag.json {
+      continue
+    }
+    if isZeroTimestamp(fv) && !tag.json {
+      continue
+    }
+    s, e := formatField(fv, tag.json)
+    if e != nil {
+      return nil, &AttributeError{Attribute: tag.name, Field: f.Name, Err: e}
+    }
+    attrs[tag.name] = s
+  }
+  return attrs, nil
+}
@@ -0,0 +1,194 @@
+package apigee
+
+import (
+  "errors"
+  "fmt"
+  "path"
+)
+
+// MaxCustomAttributes is the number of custom attributes Edge allows on a
+// developer, app, API Product or company.
+const MaxCustomAttributes = 18
+
+// Attribute holds a single custom attribute of an entity.
+type Attribute struct {
+  Name   string   `json:"name,omitempty"`
+  Value  string   `json:"value"`
+}
+
+// This is just a wrapper struct to aid in serialization and de-serialization
+// of the attributes of an entity.
+type attributesRoot struct {
+  Attributes Attributes `json:"attribute"`
+}
+
+func checkAttributeLimit(attrs Attributes) error {
+  if len(attrs) > MaxCustomAttributes {
+    return fmt.Errorf("%d custom attributes exceeds the limit of %d", len(attrs), MaxCustomAttributes)
+  }
+  return nil
+}
+
+// The following functions implement the attribute operations for any entity,
+// given the path of the entity, like developers/dev@example.com.
+
+func getAttributes(client *ApigeeClient, entityPath string) (Attributes, *Response, error) {
+  req, e := client.NewRequest("GET", path.Join(entityPath, "attributes"), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  root := attributesRoot{}
+  resp, e := client.Do(req, &root)
+  if e != nil {
+    return nil, resp, e
+  }
+  return root.Attributes, resp, e
+}
+
+func getAttribute(client *ApigeeClient, entityPath, name string) (*Attribute, *Response, error) {
+  req, e := client.NewRequest("GET", path.Join(entityPath, "attributes", name), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedAttr := Attribute{}
+  resp, e := client.Do(req, &returnedAttr)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedAttr, resp, e
+}
+
+// setAttribute updates the one attribute. Edge does not add an attribute this
+// way; if the entity does not have it, the 404 is returned as is. Adding it by
+// reading and replacing all the attributes would lose a concurrent change to
+// another attribute, so that is left to the caller, via replaceAttributes.
+func setAttribute(client *ApigeeClient, entityPath string, attr Attribute) (*Attribute, *Response, error) {
+  if attr.Name == "" {
+    return nil, nil, errors.New("must specify the Name of the attribute to set")
+  }
+  body := struct {
+    Value string `json:"value"`
+  }{attr.Value}
+  req, e := client.NewRequest("POST", path.Join(entityPath, "attributes", attr.Name), body)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedAttr := Attribute{}
+  resp, e := client.Do(req, &returnedAttr)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedAttr, resp, e
+}
+
+func deleteAttribute(client *ApigeeClient, entityPath, name string) (*Attribute, *Response, error) {
+  req, e := client.NewRequest("DELETE", path.Join(entityPath, "attributes", name), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  deletedAttr := Attribute{}
+  resp, e := client.Do(req, &deletedAttr)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &deletedAttr, resp, e
+}
+
+func replaceAttributes(client *ApigeeClient, entityPath string, attrs Attributes) (Attributes, *Response, error) {
+  if e := checkAttributeLimit(attrs); e != nil {
+    return nil, nil, e
+  }
+  if attrs == nil {
+    attrs = Attributes{}
+  }
+  req, e := client.NewRequest("POST", path.Join(entityPath, "attributes"), attributesRoot{attrs})
+  if e != nil {
+    return nil, nil, e
+  }
+  root := attributesRoot{}
+  resp, e := client.Do(req, &root)
+  if e != nil {
+    return nil, resp, e
+  }
+  return root.Attributes, resp, e
+}
+
+// GetAttribute retrieves one custom attribute of a developer.
+func (s *DevelopersServiceOp) GetAttribute(developerEmailOrId, name string) (*Attribute, *Response, error) {
+  return getAttribute(s.client, path.Join(developersPath, developerEmailOrId), name)
+}
+
+// SetAttribute updates the value of a custom attribute that the developer already has.
+func (s *DevelopersServiceOp) SetAttribute(developerEmailOrId string, attr Attribute) (*Attribute, *Response, error) {
+  return setAttribute(s.client, path.Join(developersPath, developerEmailOrId), attr)
+}
+
+// DeleteAttribute removes one custom attribute from a developer.
+func (s *DevelopersServiceOp) DeleteAttribute(developerEmailOrId, name string) (*Attribute, *Response, error) {
+  return deleteAttribute(s.client, path.Join(developersPath, developerEmailOrId), name)
+}
+
+// ReplaceAttributes replaces all the custom attributes of a developer.
+func (s *DevelopersServiceOp) ReplaceAttributes(developerEmailOrId string, attrs Attributes) (Attributes, *Response, error) {
+  return replaceAttributes(s.client, path.Join(developersPath, developerEmailOrId), attrs)
+}
+
+// GetAttribute retrieves one custom attribute of an app.
+func (s *DeveloperAppsServiceOp) GetAttribute(appName, name string) (*Attribute, *Response, error) {
+  return getAttribute(s.client, path.Join(s.ownerPath(), "apps", appName), name)
+}
+
+// SetAttribute updates the value of a custom attribute that the app already has.
+func (s *DeveloperAppsServiceOp) SetAttribute(appName string, attr Attribute) (*Attribute, *Response, error) {
+  return setAttribute(s.client, path.Join(s.ownerPath(), "apps", appName), attr)
+}
+
+// DeleteAttribute removes one custom attribute from an app.
+func (s *DeveloperAppsServiceOp) DeleteAttribute(appName, name string) (*Attribute, *Response, error) {
+  return deleteAttribute(s.client, path.Join(s.ownerPath(), "apps", appName), name)
+}
+
+// ReplaceAttributes replaces all the custom attributes of an app.
+func (s *DeveloperAppsServiceOp) ReplaceAttributes(appName string, attrs Attributes) (Attributes, *Response, error) {
+  return replaceAttributes(s.client, path.Join(s.ownerPath(), "apps", appName), attrs)
+}
+
+// GetAttribute retrieves one custom attribute of an API Product.
+func (s *ProductsServiceOp) GetAttribute(productName, name string) (*Attribute, *Response, error) {
+  return getAttribute(s.client, path.Join(productsPath, productName), name)
+}
+
+// SetAttribute updates the value of a custom attribute that the product already has.
+func (s *ProductsServiceOp) SetAttribute(productName string, attr Attribute) (*Attribute, *Response, error) {
+  return setAttribute(s.client, path.Join(productsPath, productName), attr)
+}
+
+// DeleteAttribute removes one custom attribute from an API Product.
+func (s *ProductsServiceOp) DeleteAttribute(productName, name string) (*Attribute, *Response, error) {
+  return deleteAttribute(s.client, path.Join(productsPath, productName), name)
+}
+
+// ReplaceAttributes replaces all the custom attributes of an API Product.
+func (s *ProductsServiceOp) ReplaceAttributes(productName string, attrs Attributes) (Attributes, *Response, error) {
+  return replaceAttributes(s.client, path.Join(productsPath, productName), attrs)
+}
+
+// GetAttribute retrieves one custom attribute of a company.
+func (s *CompaniesServiceOp) GetAttribute(companyName, name string) (*Attribute, *Response, error) {
+  return getAttribute(s.client, path.Join(companiesPath, companyName), name)
+}
+
+// SetAttribute updates the value of a custom attribute that the company already has.
+func (s *CompaniesServiceOp) SetAttribute(companyName string, attr Attribute) (*Attribute, *Response, error) {
+  return setAttribute(s.client, path.Join(companiesPath, companyName), attr)
+}
+
+// DeleteAttribute removes one custom attribute from a company.
+func (s *CompaniesServiceOp) DeleteAttribute(companyName, name string) (*Attribute, *Response, error) {
+  return deleteAttribute(s.client, path.Join(companiesPath, companyName), name)
+}
+
+// ReplaceAttributes replaces all the custom attributes of a company.
+func (s *CompaniesServiceOp) ReplaceAttributes(companyName string, attrs Attributes) (Attributes, *Response, error) {
+  return replaceAttributes(s.client, path.Join(companiesPath, companyName), attrs)
+}
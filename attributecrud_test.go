@@ -0,0 +1,110 @@
+package apigee
+
+import (
+  "encoding/json"
+  "fmt"
+  "io/ioutil"
+  "net/http"
+  "strings"
+  "testing"
+)
+
+const (
+  attributesJson1 = `{
+  "attribute" : [ {
+    "name" : "tier",
+    "value" : "gold"
+  }, {
+    "name" : "contactEmail",
+    "value" : "ops@example.com"
+  } ]
+}`
+)
+
+func TestAttributesRoot_RoundTrip(t *testing.T) {
+  var root attributesRoot
+  e := json.Unmarshal([]byte(attributesJson1), &root)
+  if e != nil {
+    t.Errorf("while unmarshaling, error:\n%#v\n", e)
+    return
+  }
+  if len(root.Attributes) != 2 || root.Attributes["tier"] != "gold" || root.Attributes["contactEmail"] != "ops@example.com" {
+    t.Errorf("got=%#v", root.Attributes)
+  }
+  data, e := json.Marshal(attributesRoot{Attributes{"tier": "silver"}})
+  if e != nil {
+    t.Errorf("while marshaling, error:\n%#v\n", e)
+    return
+  }
+  if string(data) != `{"attribute":[{"name":"tier","value":"silver"}]}` {
+    t.Errorf("got=%s", data)
+  }
+}
+
+func TestCheckAttributeLimit(t *testing.T) {
+  attrs := Attributes{}
+  for i := 0; i < MaxCustomAttributes; i++ {
+    attrs[fmt.Sprintf("attr%d", i)] = "v"
+  }
+  if e := checkAttributeLimit(attrs); e != nil {
+    t.Errorf("unexpected error at the limit: %v", e)
+  }
+  attrs["one-more"] = "v"
+  if e := checkAttributeLimit(attrs); e == nil {
+    t.Errorf("expected an error above the limit")
+  }
+  // the check happens before any request is made
+  _, _, e := replaceAttributes(nil, "developers/dev@example.com", attrs)
+  if e == nil {
+    t.Errorf("expected replaceAttributes to refuse %d attributes", len(attrs))
+  }
+}
+
+func TestSetAttribute(t *testing.T) {
+  testCases := []struct {
+    desc      string
+    exists    bool
+    wantErr   bool
+  }{
+    {"existing attribute", true, false},
+    // the 404 is returned, rather than replacing all the attributes
+    {"new attribute", false, true},
+  }
+  for _, tc := range testCases {
+    calls := []string{}
+    body := ""
+    client, stop := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+      p := strings.TrimPrefix(r.URL.Path, "/v1/o/org1/developers/dev@example.com")
+      calls = append(calls, r.Method+" "+p)
+      data, _ := ioutil.ReadAll(r.Body)
+      body = strings.TrimSpace(string(data))
+      if r.Method != "POST" || p != "/attributes/tier" {
+        http.Error(w, `{"code":"unexpected"}`, http.StatusBadRequest)
+        return
+      }
+      if !tc.exists {
+        http.Error(w, `{"code":"attribute.NotFound"}`, http.StatusNotFound)
+        return
+      }
+      fmt.Fprint(w, `{"name":"tier","value":"silver"}`)
+    })
+    attr, _, e := client.Developers.SetAttribute("dev@example.com", Attribute{Name: "tier", Value: "silver"})
+    stop()
+    if len(calls) != 1 || body != `{"value":"silver"}` {
+      t.Errorf("%s: calls got=%q, body got=%s", tc.desc, calls, body)
+    }
+    if tc.wantErr {
+      if !isNotFound(e) {
+        t.Errorf("%s: expected a 404, got=%v", tc.desc, e)
+      }
+      continue
+    }
+    if e != nil {
+      t.Errorf("%s: unexpected error: %v", tc.desc, e)
+      continue
+    }
+    if attr.Name != "tier" || attr.Value != "silver" {
+      t.Errorf("%s: got=%#v", tc.desc, attr)
+    }
+  }
+}
@@ -4,6 +4,8 @@ import (
   "encoding/json"
   "testing"
   "bytes"
+  "strings"
+  "time"
 )
 
 const (
@@ -121,3 +123,140 @@ func TestAttributes_Marshal(t *testing.T) {
 //   }
 // }
 
+
+type appSettings struct {
+  Tier          string            `apigee:"tier"`
+  RateLimit     int               `apigee:"rateLimit"`
+  ContactEmail  string            `apigee:"contactEmail,omitempty"`
+  Beta          bool              `apigee:"beta"`
+  GracePeriod   Timespan          `apigee:"gracePeriod"`
+  Since         Timestamp         `apigee:"since"`
+  Limits        map[string]int    `apigee:"limits,json"`
+  MaxBurst      *int              `apigee:"maxBurst"`
+  Ignored       string
+}
+
+func TestAttributesDecode(t *testing.T) {
+  attrs := Attributes{
+    "tier": "gold",
+    "rateLimit": "250",
+    "beta": "true",
+    "gracePeriod": "30d",
+    "since": "1500000000000",
+    "limits": `{"daily":1000,"monthly":20000}`,
+    "maxBurst": "20",
+    "Ignored": "not tagged",
+    "unrelated": "x",
+  }
+  var got appSettings
+  e := attrs.Decode(&got)
+  if e != nil {
+    t.Errorf("while decoding, error: %v", e)
+    return
+  }
+  if got.Tier != "gold" || got.RateLimit != 250 || !got.Beta || got.ContactEmail != "" || got.Ignored != "" {
+    t.Errorf("got=%#v", got)
+  }
+  if got.GracePeriod.Duration != 30 * 24 * time.Hour {
+    t.Errorf("gracePeriod: got=%v", got.GracePeriod.Duration)
+  }
+  if got.Since.String() != "1500000000000" {
+    t.Errorf("since: got=%v", got.Since)
+  }
+  if got.Limits["daily"] != 1000 || got.Limits["monthly"] != 20000 {
+    t.Errorf("limits: got=%v", got.Limits)
+  }
+  if got.MaxBurst == nil || *got.MaxBurst != 20 {
+    t.Errorf("maxBurst: got=%v", got.MaxBurst)
+  }
+}
+
+func TestAttributesDecode_Errors(t *testing.T) {
+  testCases := []struct {
+    attrs     Attributes
+    attribute string
+  }{
+    {Attributes{"rateLimit": "fast"}, "rateLimit"},
+    {Attributes{"beta": "maybe"}, "beta"},
+    {Attributes{"gracePeriod": "thirty days"}, "gracePeriod"},
+    {Attributes{"since": "yesterday"}, "since"},
+    {Attributes{"limits": "{not json"}, "limits"},
+  }
+  for i, tc := range testCases {
+    var got appSettings
+    e := tc.attrs.Decode(&got)
+    attrErr, ok := e.(*AttributeError)
+    if !ok {
+      t.Errorf("case %d: expected an *AttributeError, got=%#v", i, e)
+      continue
+    }
+    if attrErr.Attribute != tc.attribute || !strings.Contains(e.Error(), tc.attribute) {
+      t.Errorf("case %d: got=%v", i, e)
+    }
+  }
+  var notStruct int
+  if e := (Attributes{}).Decode(&notStruct); e == nil {
+    t.Errorf("expected an error decoding into an int")
+  }
+}
+
+func TestAttributesFrom(t *testing.T) {
+  burst := 20
+  settings := appSettings{
+    Tier: "gold",
+    RateLimit: 250,
+    Beta: true,
+    GracePeriod: Timespan{90 * time.Minute},
+    Since: Timestamp{time.Unix(1500000000, 0)},
+    Limits: map[string]int{"daily": 1000},
+    MaxBurst: &burst,
+    Ignored: "x",
+  }
+  got, e := AttributesFrom(settings)
+  if e != nil {
+    t.Errorf("while converting, error: %v", e)
+    return
+  }
+  expected := Attributes{
+    "tier": "gold",
+    "rateLimit": "250",
+    "beta": "true",
+    "gracePeriod": "1h30m0s",
+    "since": "1500000000000",
+    "limits": `{"daily":1000}`,
+    "maxBurst": "20",
+  }
+  if got.String() != expected.String() {
+    t.Errorf("got=%v, expected=%v", got, expected)
+  }
+
+  // round trip
+  var decoded appSettings
+  e = got.Decode(&decoded)
+  if e != nil {
+    t.Errorf("while decoding, error: %v", e)
+    return
+  }
+  if decoded.GracePeriod != settings.GracePeriod || decoded.RateLimit != settings.RateLimit || *decoded.MaxBurst != burst {
+    t.Errorf("decoded=%#v", decoded)
+  }
+
+  days, _ := AttributesFrom(struct {
+    Grace Timespan `apigee:"grace"`
+  }{*NewTimespan("30d")})
+  if days["grace"] != "30d" {
+    t.Errorf("grace: got=%q", days["grace"])
+  }
+
+  // a zero Timestamp has no attribute form, with or without omitempty
+  unset, e := AttributesFrom(struct {
+    Since   Timestamp   `apigee:"since"`
+    Until   *Timestamp  `apigee:"until"`
+  }{Until: &Timestamp{}})
+  if e != nil {
+    t.Errorf("zero timestamp: unexpected error: %v", e)
+  }
+  if len(unset) != 0 {
+    t.Errorf("zero timestamp: got=%v", unset)
+  }
+}
@@ -38,7 +38,9 @@ func (ce CacheExpiry) MarshalJSON() ([]byte, error) {
   valueMap["value"] = ce.ExpiryValue
   m1 := map[string]interface{}{}
   m1["valuesNull"] = ce.ValuesNull
-  m1[ce.ExpiryType] = valueMap
+  if ce.ExpiryType != "" {
+    m1[ce.ExpiryType] = valueMap
+  }
   j,_ := json.Marshal(m1)
   return []byte(j), nil
 }
@@ -28,6 +28,7 @@ var (
   cacheExpiry1 = CacheExpiry{"expiryDate","09-22-2016",false}
   cacheExpiry2 = CacheExpiry{"timeoutInSec","300",false}
   cacheExpiry3 = CacheExpiry{"timeOfDay","14:30:00",false}
+  cacheExpiry4 = CacheExpiry{}
 )
 
 
@@ -71,6 +72,7 @@ func TestCacheExpiry_Marshal(t *testing.T) {
     {"cacheJson1", cacheExpiry1, cacheJson1, false, true},
     {"cacheJson2", cacheExpiry2, cacheJson2, false, true},
     {"cacheJson3", cacheExpiry3, cacheJson3, false, true},
+    {"unset", cacheExpiry4, `{ "valuesNull" : false }`, false, true},
   }
 
   for _, tc := range testCases {
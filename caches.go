@@ -2,6 +2,8 @@ package apigee
 
 import (
   "path"
+  "net/url"
+  "errors"
 )
 
 const cachesPath = "caches"
@@ -11,6 +13,11 @@ const cachesPath = "caches"
 type CachesService interface {
   List(string) ([]string, *Response, error)
   Get(string, string) (*Cache, *Response, error)
+  Create(Cache, string) (*Cache, *Response, error)
+  Update(Cache, string) (*Cache, *Response, error)
+  Delete(string, string) (*Cache, *Response, error)
+  ClearAll(string, string) (*Response, error)
+  ClearEntry(string, string, string, string) (*Response, error)
 }
 
 type CachesServiceOp struct {
@@ -31,19 +38,38 @@ type Cache struct {
   MaxElementsInMemory  int      `json:"maxElementsInMemory,omitempty"`
   MaxElementsOnDisk    int           `json:"maxElementsOnDisk,omitempty"`
   Expiry               CacheExpiry   `json:"expirySettings,omitempty"`
+  // fields returned by Edge that are not modeled above; sent back on Update
+  Extra                ExtraFields   `json:"-"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Fields that
+// Cache does not model are kept in Extra.
+func (cache *Cache) UnmarshalJSON(data []byte) error {
+  type plain Cache
+  return unmarshalWithExtra(data, (*plain)(cache), &cache.Extra)
+}
+
+// MarshalJSON implements the json.Marshaler interface, including the Extra fields.
+func (cache Cache) MarshalJSON() ([]byte, error) {
+  type plain Cache
+  return marshalWithExtra(plain(cache), cache.Extra)
 }
 
 
+// cachePath returns the path for caches in the environment, or in the
+// organization if env is empty, with the given elements appended.
+func cachePath(env string, elements ...string) string {
+  p := cachesPath
+  if env != "" {
+    p = path.Join("e", env, cachesPath)
+  }
+  return path.Join(append([]string{p}, elements...)...)
+}
+
 // List retrieves the list of cache names for the organization referred by the ApigeeClient,
 // or a set of cache names for a specific environment within an organization.
 func (s *CachesServiceOp) List(env string) ([]string, *Response, error) {
-  var p1 string
-  if env == "" {
-    p1 = cachesPath
-  } else {
-    p1 = path.Join("e", env, cachesPath)
-  }
-  req, e := s.client.NewRequest("GET", p1, nil)
+  req, e := s.client.NewRequest("GET", cachePath(env), nil)
   if e != nil {
     return nil, nil, e
   }
@@ -59,13 +85,33 @@ func (s *CachesServiceOp) List(env string) ([]string, *Response, error) {
 // cache in an environment within an organization. This information includes the
 // properties, and the created and last modified details.
 func (s *CachesServiceOp) Get(name, env string) (*Cache, *Response, error) {
-  var p1 string
-  if env == "" {
-    p1 = path.Join(cachesPath, env)
-  } else {
-    p1 = path.Join("e", env, cachesPath)
+  req, e := s.client.NewRequest("GET", cachePath(env, name), nil)
+  if e != nil {
+    return nil, nil, e
   }
-  req, e := s.client.NewRequest("GET", p1, nil)
+  returnedCache := Cache{}
+  resp, e := s.client.Do(req, &returnedCache)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedCache, resp, e
+}
+
+// Create creates a cache in an environment, or in the organization if env is empty.
+func (s *CachesServiceOp) Create(cache Cache, env string) (*Cache, *Response, error) {
+  if cache.Name == "" {
+    return nil, nil, errors.New("cannot create a cache with no name")
+  }
+  // append the query param
+  origURL, e := url.Parse(cachePath(env))
+  if e != nil {
+    return nil, nil, e
+  }
+  q := origURL.Query()
+  q.Add("name", cache.Name)
+  origURL.RawQuery = q.Encode()
+
+  req, e := s.client.NewRequest("POST", origURL.String(), cache)
   if e != nil {
     return nil, nil, e
   }
@@ -76,3 +122,75 @@ func (s *CachesServiceOp) Get(name, env string) (*Cache, *Response, error) {
   }
   return &returnedCache, resp, e
 }
+
+// Update replaces the settings of an existing cache.
+func (s *CachesServiceOp) Update(cache Cache, env string) (*Cache, *Response, error) {
+  if cache.Name == "" {
+    return nil, nil, errors.New("must specify the Name of the cache to update")
+  }
+  req, e := s.client.NewRequest("PUT", cachePath(env, cache.Name), cache)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedCache := Cache{}
+  resp, e := s.client.Do(req, &returnedCache)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedCache, resp, e
+}
+
+// Delete removes a cache.
+func (s *CachesServiceOp) Delete(name, env string) (*Cache, *Response, error) {
+  req, e := s.client.NewRequest("DELETE", cachePath(env, name), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  deletedCache := Cache{}
+  resp, e := s.client.Do(req, &deletedCache)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &deletedCache, resp, e
+}
+
+func clearCacheEntries(s CachesServiceOp, entriesPath string) (*Response, error) {
+  // the path may hold an escaped entry name, so append the query param
+  // without parsing it
+  req, e := s.client.NewRequest("POST", entriesPath + "?action=clear", nil)
+  if e != nil {
+    return nil, e
+  }
+  return s.client.Do(req, nil)
+}
+
+// ClearAll removes all entries from a cache. This is useful for flushing
+// response caches after a release.
+func (s *CachesServiceOp) ClearAll(name, env string) (*Response, error) {
+  return clearCacheEntries(*s, cachePath(env, name, "entries"))
+}
+
+// ClearEntry removes a single entry from a cache. The key is the cache key as
+// built by the cache policy. If the policy specifies a Prefix, pass it as
+// prefix; Edge stores such entries under the key "prefix__key".
+func (s *CachesServiceOp) ClearEntry(name, env, key, prefix string) (*Response, error) {
+  entryPath, e := cacheEntryPath(name, env, key, prefix)
+  if e != nil {
+    return nil, e
+  }
+  return clearCacheEntries(*s, entryPath)
+}
+
+// cacheEntryPath returns the path of a single cache entry, whose name is the
+// key, or "prefix__key" if there is a prefix. The name is escaped, because
+// cache keys often hold characters like / or ? that are special in a URL.
+func cacheEntryPath(name, env, key, prefix string) (string, error) {
+  if key == "" {
+    return "", errors.New("must specify the key of the cache entry to clear")
+  }
+  entry := key
+  if prefix != "" {
+    entry = prefix + "__" + key
+  }
+  return cachePath(env, name, "entries", url.PathEscape(entry)), nil
+}
@@ -0,0 +1,72 @@
+package apigee
+
+import (
+  "net/http"
+  "testing"
+)
+
+func TestCachePath(t *testing.T) {
+  testCases := []struct {
+    got      string
+    expected string
+  }{
+    {cachePath(""), "caches"},
+    {cachePath("", "c1"), "caches/c1"},
+    {cachePath("test"), "e/test/caches"},
+    {cachePath("test", "c1"), "e/test/caches/c1"},
+    {cachePath("prod", "c1", "entries", "p1__k1"), "e/prod/caches/c1/entries/p1__k1"},
+  }
+  for _, tc := range testCases {
+    if tc.got != tc.expected {
+      t.Errorf("got=%s, expected=%s", tc.got, tc.expected)
+    }
+  }
+}
+
+func TestCacheEntryPath(t *testing.T) {
+  testCases := []struct {
+    name, env, key, prefix string
+    expected               string
+    wantErr                bool
+  }{
+    {"c1", "test", "k1", "", "e/test/caches/c1/entries/k1", false},
+    {"c1", "test", "k1", "p1", "e/test/caches/c1/entries/p1__k1", false},
+    {"c1", "", "k1", "p1", "caches/c1/entries/p1__k1", false},
+    {"c1", "test", "/v1/flights?id=7&x=#top", "", "e/test/caches/c1/entries/%2Fv1%2Fflights%3Fid=7&x=%23top", false},
+    {"c1", "test", "", "", "", true},
+    {"c1", "test", "", "p1", "", true},
+  }
+  for i, tc := range testCases {
+    got, e := cacheEntryPath(tc.name, tc.env, tc.key, tc.prefix)
+    if tc.wantErr {
+      if e == nil {
+        t.Errorf("case %d: expected an error, got=%q", i, got)
+      }
+      continue
+    }
+    if e != nil {
+      t.Errorf("case %d: unexpected error: %v", i, e)
+      continue
+    }
+    if got != tc.expected {
+      t.Errorf("case %d: got=%s, expected=%s", i, got, tc.expected)
+    }
+  }
+}
+
+func TestClearEntry_EscapedKey(t *testing.T) {
+  var requestURI string
+  client, stop := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+    requestURI = r.RequestURI
+  })
+  defer stop()
+  _, e := client.Caches.ClearEntry("c1", "test", "/v1/flights?id=7", "p1")
+  if e != nil {
+    t.Errorf("unexpected error: %v", e)
+    return
+  }
+  expected := "/v1/o/org1/e/test/caches/c1/entries/p1__%2Fv1%2Fflights%3Fid=7?action=clear"
+  if requestURI != expected {
+    t.Errorf("got=%s, expected=%s", requestURI, expected)
+  }
+}
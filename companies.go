@@ -0,0 +1,231 @@
+package apigee
+
+import (
+  "path"
+  "net/url"
+  "errors"
+)
+
+const companiesPath = "companies"
+
+// CompaniesService is an interface for interfacing with the Apigee Edge Admin API
+// dealing with companies. A company groups developers, and can own apps in its
+// own right.
+type CompaniesService interface {
+  List() ([]string, *Response, error)
+  Get(string) (*Company, *Response, error)
+  Create(Company) (*Company, *Response, error)
+  Update(Company) (*Company, *Response, error)
+  Delete(string) (*Company, *Response, error)
+  Activate(string) (*Response, error)
+  Deactivate(string) (*Response, error)
+  ListDevelopers(string) ([]CompanyDeveloper, *Response, error)
+  AddDevelopers(string, []CompanyDeveloper) ([]CompanyDeveloper, *Response, error)
+  RemoveDeveloper(string, string) (*CompanyDeveloper, *Response, error)
+  Apps(string) (CompanyAppsService)
+  GetAttribute(string, string) (*Attribute, *Response, error)
+  SetAttribute(string, Attribute) (*Attribute, *Response, error)
+  DeleteAttribute(string, string) (*Attribute, *Response, error)
+  ReplaceAttributes(string, Attributes) (Attributes, *Response, error)
+}
+
+// CompanyAppsService is an interface for interfacing with the Apigee Edge Admin
+// API dealing with apps that belong to a particular company. It has the same
+// operations as DeveloperAppsService, including those for credentials.
+type CompanyAppsService interface {
+  DeveloperAppsService
+}
+
+type CompaniesServiceOp struct {
+  client *ApigeeClient
+}
+
+var _ CompaniesService = &CompaniesServiceOp{}
+
+// Company contains information about a company within an Edge organization.
+type Company struct {
+  Name             string      `json:"name,omitempty"`
+  DisplayName      string      `json:"displayName,omitempty"`
+  Status           string      `json:"status,omitempty"` // active, inactive
+  Attributes       Attributes  `json:"attributes,omitempty"`
+  Apps             []string    `json:"apps,omitempty"`
+  OrganizationName string      `json:"organization,omitempty"`
+  CreatedBy        string      `json:"createdBy,omitempty"`
+  CreatedAt        Timestamp   `json:"createdAt,omitempty"`
+  LastModifiedBy   string      `json:"lastModifiedBy,omitempty"`
+  LastModifiedAt   Timestamp   `json:"lastModifiedAt,omitempty"`
+}
+
+// CompanyDeveloper identifies a developer that belongs to a company, and the
+// role of the developer within the company.
+type CompanyDeveloper struct {
+  Email  string   `json:"email,omitempty"`
+  Role   string   `json:"role,omitempty"`
+}
+
+// This is just a wrapper struct to aid in serialization and de-serialization
+// of company developer lists.
+type companyDevelopersRoot struct {
+  Developers []CompanyDeveloper `json:"developer"`
+}
+
+func (s *CompaniesServiceOp) List() ([]string, *Response, error) {
+  req, e := s.client.NewRequest("GET", companiesPath, nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  namelist := make([]string,0)
+  resp, e := s.client.Do(req, &namelist)
+  if e != nil {
+    return nil, resp, e
+  }
+  return namelist, resp, e
+}
+
+func (s *CompaniesServiceOp) Get(companyName string) (*Company, *Response, error) {
+  req, e := s.client.NewRequest("GET", path.Join(companiesPath, companyName), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedCompany := Company{}
+  resp, e := s.client.Do(req, &returnedCompany)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedCompany, resp, e
+}
+
+func (s *CompaniesServiceOp) Create(company Company) (*Company, *Response, error) {
+  if company.Name == "" {
+    return nil, nil, errors.New("cannot create a company with no name")
+  }
+  req, e := s.client.NewRequest("POST", companiesPath, company)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedCompany := Company{}
+  resp, e := s.client.Do(req, &returnedCompany)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedCompany, resp, e
+}
+
+// Update changes the display name and attributes of a company. Edge replaces
+// the attributes with those passed.
+func (s *CompaniesServiceOp) Update(company Company) (*Company, *Response, error) {
+  if company.Name == "" {
+    return nil, nil, errors.New("must specify the Name of the company to update")
+  }
+  req, e := s.client.NewRequest("POST", path.Join(companiesPath, company.Name), company)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedCompany := Company{}
+  resp, e := s.client.Do(req, &returnedCompany)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedCompany, resp, e
+}
+
+// Delete removes a company. Edge rejects this if the company still owns apps.
+func (s *CompaniesServiceOp) Delete(companyName string) (*Company, *Response, error) {
+  req, e := s.client.NewRequest("DELETE", path.Join(companiesPath, companyName), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  deletedCompany := Company{}
+  resp, e := s.client.Do(req, &deletedCompany)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &deletedCompany, resp, e
+}
+
+func updateCompanyStatus (s CompaniesServiceOp, companyName string, desiredStatus string) (*Response, error) {
+  companyPath := path.Join(companiesPath, companyName)
+
+  // append the necessary query param
+  origURL, e := url.Parse(companyPath)
+  if e != nil {
+     return nil, e
+  }
+  q := origURL.Query()
+  q.Add("action", desiredStatus)
+  origURL.RawQuery = q.Encode()
+  companyPath = origURL.String()
+
+  req, e := s.client.NewRequest("POST", companyPath, nil)
+  if e != nil {
+    return nil, e
+  }
+  resp, e := s.client.Do(req, nil)
+  if e != nil {
+    return resp, e
+  }
+  return resp, e
+}
+
+// Activate sets the status of a company to active.
+func (s *CompaniesServiceOp) Activate(companyName string) (*Response, error) {
+  return updateCompanyStatus(*s, companyName, "active")
+}
+
+// Deactivate sets the status of a company to inactive. The apps of an inactive
+// company cannot be used.
+func (s *CompaniesServiceOp) Deactivate(companyName string) (*Response, error) {
+  return updateCompanyStatus(*s, companyName, "inactive")
+}
+
+// ListDevelopers retrieves the developers that belong to a company, with their roles.
+func (s *CompaniesServiceOp) ListDevelopers(companyName string) ([]CompanyDeveloper, *Response, error) {
+  req, e := s.client.NewRequest("GET", path.Join(companiesPath, companyName, "developers"), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  root := companyDevelopersRoot{}
+  resp, e := s.client.Do(req, &root)
+  if e != nil {
+    return nil, resp, e
+  }
+  return root.Developers, resp, e
+}
+
+// AddDevelopers adds developers to a company, or changes the roles of
+// developers already in it. The developers must already exist.
+func (s *CompaniesServiceOp) AddDevelopers(companyName string, developers []CompanyDeveloper) ([]CompanyDeveloper, *Response, error) {
+  if len(developers) == 0 {
+    return nil, nil, errors.New("must specify at least one developer to add")
+  }
+  req, e := s.client.NewRequest("POST", path.Join(companiesPath, companyName, "developers"), companyDevelopersRoot{developers})
+  if e != nil {
+    return nil, nil, e
+  }
+  root := companyDevelopersRoot{}
+  resp, e := s.client.Do(req, &root)
+  if e != nil {
+    return nil, resp, e
+  }
+  return root.Developers, resp, e
+}
+
+// RemoveDeveloper removes a developer from a company. The developer itself is
+// not deleted.
+func (s *CompaniesServiceOp) RemoveDeveloper(companyName, developerEmail string) (*CompanyDeveloper, *Response, error) {
+  req, e := s.client.NewRequest("DELETE", path.Join(companiesPath, companyName, "developers", developerEmail), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  removedDeveloper := CompanyDeveloper{}
+  resp, e := s.client.Do(req, &removedDeveloper)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &removedDeveloper, resp, e
+}
+
+// Apps returns a service that manages the apps of a company.
+func (s *CompaniesServiceOp) Apps(companyName string) CompanyAppsService {
+  return &DeveloperAppsServiceOp{client: s.client, companyName: companyName}
+}
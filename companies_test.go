@@ -0,0 +1,91 @@
+package apigee
+
+import (
+  "encoding/json"
+  "testing"
+)
+
+const (
+  companyJson1 = `{
+  "apps" : [ "partner-app" ],
+  "attributes" : [ { "name" : "tier", "value" : "gold" } ],
+  "createdAt" : 1500000000000,
+  "createdBy" : "admin@example.com",
+  "displayName" : "Acme Travel",
+  "lastModifiedAt" : 1600000000000,
+  "lastModifiedBy" : "admin@example.com",
+  "name" : "acme",
+  "organization" : "cheeso",
+  "status" : "active"
+}`
+
+  companyDevelopersJson1 = `{
+  "developer" : [ {
+    "email" : "alice@example.com",
+    "role" : "admin"
+  }, {
+    "email" : "bob@example.com",
+    "role" : "developer"
+  } ]
+}`
+)
+
+func TestCompany_Unmarshal(t *testing.T) {
+  var got Company
+  e := json.Unmarshal([]byte(companyJson1), &got)
+  if e != nil {
+    t.Errorf("while unmarshaling, error:\n%#v\n", e)
+    return
+  }
+  if got.Name != "acme" || got.DisplayName != "Acme Travel" || got.Status != "active" ||
+    got.OrganizationName != "cheeso" || got.Attributes["tier"] != "gold" ||
+    len(got.Apps) != 1 || got.CreatedAt.String() != "1500000000000" {
+    t.Errorf("got=%#v", got)
+  }
+}
+
+func TestCompanyDevelopers_RoundTrip(t *testing.T) {
+  var root companyDevelopersRoot
+  e := json.Unmarshal([]byte(companyDevelopersJson1), &root)
+  if e != nil {
+    t.Errorf("while unmarshaling, error:\n%#v\n", e)
+    return
+  }
+  expected := []CompanyDeveloper{{"alice@example.com", "admin"}, {"bob@example.com", "developer"}}
+  if len(root.Developers) != len(expected) {
+    t.Errorf("got=%#v, expected=%#v", root.Developers, expected)
+    return
+  }
+  for i := range expected {
+    if root.Developers[i] != expected[i] {
+      t.Errorf("%d: got=%#v, expected=%#v", i, root.Developers[i], expected[i])
+    }
+  }
+  body, e := json.Marshal(companyDevelopersRoot{expected[:1]})
+  if e != nil {
+    t.Errorf("while marshaling, error:\n%#v\n", e)
+    return
+  }
+  if string(body) != `{"developer":[{"email":"alice@example.com","role":"admin"}]}` {
+    t.Errorf("got=%s", body)
+  }
+}
+
+func TestAppsOwnerPath(t *testing.T) {
+  companies := &CompaniesServiceOp{}
+  companyApps := companies.Apps("acme").(*DeveloperAppsServiceOp)
+  developerApps := &DeveloperAppsServiceOp{developerId: "dev@example.com"}
+  testCases := []struct {
+    got      string
+    expected string
+  }{
+    {companyApps.ownerPath(), "companies/acme"},
+    {companyApps.keyPath("partner-app", "key1"), "companies/acme/apps/partner-app/keys/key1"},
+    {developerApps.ownerPath(), "developers/dev@example.com"},
+  }
+  for i, tc := range testCases {
+    if tc.got != tc.expected {
+      t.Errorf("case %d: got=%q, expected=%q", i, tc.got, tc.expected)
+    }
+  }
+}
@@ -0,0 +1,212 @@
+package apigee
+
+import (
+  "fmt"
+  "os"
+  "path"
+  "path/filepath"
+  "strings"
+)
+
+// ConflictPolicy tells the client what to do when deploying an API Proxy
+// revision would put it at the same basepath, on the same virtual host, as
+// another API Proxy that is already deployed in the environment.
+type ConflictPolicy int
+
+const (
+  // IgnoreConflicts deploys without checking. This is the default.
+  IgnoreConflicts ConflictPolicy = iota
+  // WarnOnConflicts deploys, and reports each conflict in the Conflicts of the
+  // RevisionDeployment that Deploy returns.
+  WarnOnConflicts
+  // RefuseConflicts returns a BasePathConflicts error, and does not deploy.
+  RefuseConflicts
+)
+
+// anyVirtualHost stands for the virtual hosts of a ProxyEndpoint that names
+// none. Edge exposes such an endpoint on every virtual host in the environment.
+const anyVirtualHost = "*"
+
+// BasePathConflict describes a ProxyEndpoint of a candidate API Proxy revision
+// that would listen at the same basepath, on the same virtual host, as a
+// ProxyEndpoint of another deployed API Proxy.
+type BasePathConflict struct {
+  BasePath          string
+  VirtualHost       string
+  Endpoint          string
+  DeployedProxy     string
+  DeployedRevision  Revision
+  DeployedEndpoint  string
+}
+
+func (c BasePathConflict) String() string {
+  return fmt.Sprintf("endpoint %s at %s on virtual host %s conflicts with %s revision %d endpoint %s",
+    c.Endpoint, c.BasePath, c.VirtualHost, c.DeployedProxy, c.DeployedRevision, c.DeployedEndpoint)
+}
+
+// BasePathConflicts is the error returned by Deploy when the client refuses to
+// deploy a revision because of conflicts.
+type BasePathConflicts []BasePathConflict
+
+func (c BasePathConflicts) Error() string {
+  descriptions := make([]string, 0, len(c))
+  for _, conflict := range c {
+    descriptions = append(descriptions, conflict.String())
+  }
+  return "basepath conflicts: " + strings.Join(descriptions, "; ")
+}
+
+// deployedConnections holds the ProxyEndpoint connections of one API Proxy
+// revision, along with the basepath it was deployed at.
+type deployedConnections struct {
+  Name         string
+  Revision     Revision
+  BasePath     string
+  Connections  []ProxyEndpointConnection
+}
+
+// effectiveBasePath combines the basepath of a deployment with the basepath of
+// a ProxyEndpoint, the way Edge does when routing requests.
+func effectiveBasePath(deploymentBasePath, endpointBasePath string) string {
+  return path.Clean("/" + path.Join(deploymentBasePath, endpointBasePath))
+}
+
+func virtualHostsOf(connection ProxyEndpointConnection) []string {
+  if len(connection.VirtualHosts) == 0 {
+    return []string{anyVirtualHost}
+  }
+  return connection.VirtualHosts
+}
+
+// sharedVirtualHost returns a virtual host on which both connections listen,
+// and true, or false if there is none.
+func sharedVirtualHost(a, b ProxyEndpointConnection) (string, bool) {
+  for _, va := range virtualHostsOf(a) {
+    for _, vb := range virtualHostsOf(b) {
+      if va == vb || vb == anyVirtualHost {
+        return va, true
+      }
+      if va == anyVirtualHost {
+        return vb, true
+      }
+    }
+  }
+  return "", false
+}
+
+// findBasePathConflicts compares the connections of a candidate revision, to be
+// deployed at the given basepath, against the connections of deployed revisions.
+// Deployed revisions of the candidate proxy itself are skipped, because the
+// deploy replaces them.
+func findBasePathConflicts(proxyName string, candidate []ProxyEndpointConnection, basepath string, deployed []deployedConnections) []BasePathConflict {
+  conflicts := []BasePathConflict{}
+  for _, c := range candidate {
+    candidatePath := effectiveBasePath(basepath, c.BasePath)
+    for _, d := range deployed {
+      if d.Name == proxyName {
+        continue
+      }
+      for _, dc := range d.Connections {
+        if effectiveBasePath(d.BasePath, dc.BasePath) != candidatePath {
+          continue
+        }
+        if vhost, ok := sharedVirtualHost(c, dc); ok {
+          conflicts = append(conflicts, BasePathConflict{
+            BasePath: candidatePath,
+            VirtualHost: vhost,
+            Endpoint: c.Endpoint,
+            DeployedProxy: d.Name,
+            DeployedRevision: d.Revision,
+            DeployedEndpoint: dc.Endpoint,
+          })
+        }
+      }
+    }
+  }
+  return conflicts
+}
+
+// environmentConnections retrieves the ProxyEndpoint connections of every API
+// Proxy revision deployed in an environment, except those of the named proxy.
+func environmentConnections(client *ApigeeClient, proxyName, env string) ([]deployedConnections, error) {
+  inventory, e := client.Environments.GetDeployments(env)
+  if e != nil {
+    return nil, e
+  }
+  deployed := []deployedConnections{}
+  for _, d := range inventory {
+    if d.Type != apiUriPathElement || d.Name == proxyName {
+      continue
+    }
+    connections, e := proxyConnections(client, d.Name, d.Revision.Number)
+    if e != nil {
+      return nil, e
+    }
+    deployed = append(deployed, deployedConnections{Name: d.Name, Revision: d.Revision.Number, BasePath: d.Revision.BasePath(), Connections: connections})
+  }
+  return deployed, nil
+}
+
+// bundleConnections reads the ProxyEndpoint connections from a local bundle.
+// The source can be a zip file or a directory containing an apiproxy
+// directory, the same as for Import.
+func bundleConnections(source string) ([]ProxyEndpointConnection, error) {
+  info, e := os.Stat(source)
+  if e != nil {
+    return nil, e
+  }
+  var entries []bundleEntry
+  if info.IsDir() {
+    entries, e = readDirBundleEntries(filepath.Join(source, "apiproxy"))
+  } else {
+    entries, e = readZipBundleEntries(source)
+  }
+  if e != nil {
+    return nil, e
+  }
+  connections := []ProxyEndpointConnection{}
+  for _, entry := range entries {
+    if path.Dir(entry.name) != "apiproxy/proxies" || path.Ext(entry.name) != ".xml" {
+      continue
+    }
+    connection, e := parseProxyEndpointConnection(string(entry.content))
+    if e != nil {
+      return nil, fmt.Errorf("while parsing %s, error: %v", entry.name, e)
+    }
+    if connection.Endpoint == "" {
+      connection.Endpoint = strings.TrimSuffix(path.Base(entry.name), ".xml")
+    }
+    connections = append(connections, *connection)
+  }
+  return connections, nil
+}
+
+// checkConflicts returns the conflicts between the candidate connections of the
+// named proxy and the API Proxies deployed in the environment.
+func checkConflicts(client *ApigeeClient, proxyName string, candidate []ProxyEndpointConnection, basepath, env string) ([]BasePathConflict, error) {
+  deployed, e := environmentConnections(client, proxyName, env)
+  if e != nil {
+    return nil, e
+  }
+  return findBasePathConflicts(proxyName, candidate, basepath, deployed), nil
+}
+
+// checkDeployConflicts applies the ConflictPolicy of the client before a
+// revision of an API Proxy is deployed. Under RefuseConflicts, any conflicts are
+// returned as a BasePathConflicts error; otherwise they are returned for the
+// caller to report. Deploy does not call it under IgnoreConflicts, to spare the
+// requests the check makes.
+func checkDeployConflicts(client *ApigeeClient, proxyName, basepath, env string, rev Revision) ([]BasePathConflict, error) {
+  candidate, e := proxyConnections(client, proxyName, rev)
+  if e != nil {
+    return nil, e
+  }
+  conflicts, e := checkConflicts(client, proxyName, candidate, basepath, env)
+  if e != nil || len(conflicts) == 0 {
+    return nil, e
+  }
+  if client.Options.BasePathConflicts == RefuseConflicts {
+    return nil, BasePathConflicts(conflicts)
+  }
+  return conflicts, nil
+}
@@ -0,0 +1,149 @@
+package apigee
+
+import (
+  "fmt"
+  "io/ioutil"
+  "net/http"
+  "os"
+  "path/filepath"
+  "strings"
+  "testing"
+)
+
+func TestFindBasePathConflicts(t *testing.T) {
+  deployed := []deployedConnections{
+    {Name: "flights", Revision: 4, BasePath: "/", Connections: []ProxyEndpointConnection{
+      {Endpoint: "default", BasePath: "/v1/flights", VirtualHosts: []string{"secure"}},
+    }},
+    {Name: "hotels", Revision: 2, BasePath: "/", Connections: []ProxyEndpointConnection{
+      {Endpoint: "default", BasePath: "/v1/hotels"},
+    }},
+    {Name: "cars", Revision: 1, BasePath: "/travel", Connections: []ProxyEndpointConnection{
+      {Endpoint: "default", BasePath: "/cars/", VirtualHosts: []string{"default"}},
+    }},
+  }
+  testCases := []struct {
+    proxyName string
+    candidate ProxyEndpointConnection
+    basepath  string
+    expected  []string
+  }{
+    {"flights2", ProxyEndpointConnection{"default", "/v1/flights", []string{"secure"}}, "", []string{"flights"}},
+    {"flights2", ProxyEndpointConnection{"default", "/v1/flights", []string{"default"}}, "", []string{}},
+    {"flights2", ProxyEndpointConnection{"default", "/v1/flights", nil}, "", []string{"flights"}},
+    {"flights", ProxyEndpointConnection{"default", "/v1/flights", []string{"secure"}}, "", []string{}},
+    {"hotels2", ProxyEndpointConnection{"default", "/v1/hotels", []string{"default"}}, "/", []string{"hotels"}},
+    {"cars2", ProxyEndpointConnection{"default", "/cars", []string{"default", "secure"}}, "/travel", []string{"cars"}},
+    {"cars2", ProxyEndpointConnection{"default", "/cars", []string{"default"}}, "", []string{}},
+  }
+  for i, tc := range testCases {
+    got := findBasePathConflicts(tc.proxyName, []ProxyEndpointConnection{tc.candidate}, tc.basepath, deployed)
+    if len(got) != len(tc.expected) {
+      t.Errorf("case %d: got=%#v, expected=%v", i, got, tc.expected)
+      continue
+    }
+    for j := range got {
+      if got[j].DeployedProxy != tc.expected[j] {
+        t.Errorf("case %d: got=%#v, expected=%v", i, got[j], tc.expected[j])
+      }
+    }
+  }
+}
+
+func TestBasePathConflicts_Error(t *testing.T) {
+  e := BasePathConflicts{{BasePath: "/v1/flights", VirtualHost: "secure", Endpoint: "default",
+    DeployedProxy: "flights", DeployedRevision: 4, DeployedEndpoint: "default"}}
+  expected := "basepath conflicts: endpoint default at /v1/flights on virtual host secure conflicts with flights revision 4 endpoint default"
+  if e.Error() != expected {
+    t.Errorf("got=%q, expected=%q", e.Error(), expected)
+  }
+}
+
+func TestBundleConnections(t *testing.T) {
+  tempDir, e := ioutil.TempDir("", "go-apigee-test-")
+  if e != nil {
+    t.Fatalf("while creating temp dir, error: %v", e)
+  }
+  defer os.RemoveAll(tempDir)
+  proxiesDir := filepath.Join(tempDir, "apiproxy", "proxies")
+  e = os.MkdirAll(proxiesDir, 0755)
+  if e != nil {
+    t.Fatalf("while creating dir, error: %v", e)
+  }
+  endpointXml := `<ProxyEndpoint name="default">
+  <HTTPProxyConnection>
+    <BasePath>/v1/flights</BasePath>
+    <VirtualHost>secure</VirtualHost>
+  </HTTPProxyConnection>
+</ProxyEndpoint>`
+  e = ioutil.WriteFile(filepath.Join(proxiesDir, "default.xml"), []byte(endpointXml), 0644)
+  if e != nil {
+    t.Fatalf("while writing file, error: %v", e)
+  }
+  got, e := bundleConnections(tempDir)
+  if e != nil {
+    t.Errorf("while reading connections, error: %v", e)
+    return
+  }
+  if len(got) != 1 || got[0].Endpoint != "default" || got[0].BasePath != "/v1/flights" ||
+    len(got[0].VirtualHosts) != 1 || got[0].VirtualHosts[0] != "secure" {
+    t.Errorf("got=%#v", got)
+  }
+}
+
+func TestDeploy_ConflictPolicy(t *testing.T) {
+  endpointXml := `<ProxyEndpoint name="default"><HTTPProxyConnection><BasePath>/v1</BasePath><VirtualHost>secure</VirtualHost></HTTPProxyConnection></ProxyEndpoint>`
+  testCases := []struct {
+    policy     ConflictPolicy
+    requests   int
+    conflicts  int
+    wantErr    bool
+  }{
+    // only the deploy itself
+    {IgnoreConflicts, 1, 0, false},
+    // the candidate revision and endpoint, both deployments lists, the
+    // deployed revision and endpoint, and the deploy
+    {WarnOnConflicts, 7, 1, false},
+    {RefuseConflicts, 6, 0, true},
+  }
+  for _, tc := range testCases {
+    requests := []string{}
+    client, stop := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+      p := strings.TrimPrefix(r.URL.Path, "/v1/o/org1/")
+      requests = append(requests, r.Method+" "+p)
+      switch {
+      case r.Method == "POST" && p == "apis/flights/revisions/2/deployments":
+        fmt.Fprint(w, `{"name":"2","state":"deployed"}`)
+      case p == "apis/flights/revisions/2" || p == "apis/hotels/revisions/5":
+        fmt.Fprint(w, `{"proxyEndpoints":["default"]}`)
+      case strings.HasSuffix(p, "/proxies/default"):
+        fmt.Fprint(w, endpointXml)
+      case p == "e/test/deployments" && r.URL.Query().Get("sharedFlows") == "":
+        fmt.Fprint(w, `{"name":"test","aPIProxy":[{"name":"hotels","revision":[{"name":"5","state":"deployed"}]}]}`)
+      case p == "e/test/deployments":
+        fmt.Fprint(w, `{"name":"test"}`)
+      default:
+        http.Error(w, `{"code":"unexpected"}`, http.StatusBadRequest)
+      }
+    })
+    client.Options.BasePathConflicts = tc.policy
+    deployment, _, e := client.Proxies.Deploy("flights", "test", 2)
+    stop()
+    if len(requests) != tc.requests {
+      t.Errorf("policy %d: requests got=%q, expected %d", tc.policy, requests, tc.requests)
+    }
+    if tc.wantErr {
+      if _, ok := e.(BasePathConflicts); !ok {
+        t.Errorf("policy %d: expected BasePathConflicts, got=%v", tc.policy, e)
+      }
+      continue
+    }
+    if e != nil {
+      t.Errorf("policy %d: unexpected error: %v", tc.policy, e)
+      continue
+    }
+    if len(deployment.Conflicts) != tc.conflicts {
+      t.Errorf("policy %d: conflicts got=%v, expected %d", tc.policy, deployment.Conflicts, tc.conflicts)
+    }
+  }
+}
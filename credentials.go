@@ -0,0 +1,231 @@
+package apigee
+
+import (
+  "errors"
+  "fmt"
+  "net/url"
+  "path"
+  "time"
+)
+
+// Credential holds a consumer key and secret issued to an app, along with the
+// API Products the key grants access to.
+type Credential struct {
+  ConsumerKey     string               `json:"consumerKey,omitempty"`
+  ConsumerSecret  string               `json:"consumerSecret,omitempty"`
+  Status          string               `json:"status,omitempty"`
+  ExpiresAt       Timestamp            `json:"expiresAt,omitempty"`
+  IssuedAt        Timestamp            `json:"issuedAt,omitempty"`
+  ApiProducts     []CredentialProduct  `json:"apiProducts,omitempty"`
+  Scopes          []string             `json:"scopes,omitempty"`
+  Attributes      Attributes           `json:"attributes,omitempty"`
+}
+
+// CredentialProduct holds the name of an API Product associated to a consumer
+// key, and the status of that association: "approved", "pending" or "revoked".
+type CredentialProduct struct {
+  Name    string   `json:"apiproduct,omitempty"`
+  Status  string   `json:"status,omitempty"`
+}
+
+// NeverExpires returns true if the key has no expiry. Edge reports this as an
+// expiresAt of -1.
+func (c Credential) NeverExpires() bool {
+  return c.ExpiresAt.Time.IsZero() || c.ExpiresAt.Time.UnixNano() / 1000000 == -1
+}
+
+// ExpiresBefore returns true if the key expires before the given time.
+func (c Credential) ExpiresBefore(t time.Time) bool {
+  return !c.NeverExpires() && c.ExpiresAt.Time.Before(t)
+}
+
+// ProductNames returns the names of the API Products associated to the key.
+func (c Credential) ProductNames() []string {
+  names := make([]string, 0, len(c.ApiProducts))
+  for _, p := range c.ApiProducts {
+    names = append(names, p.Name)
+  }
+  return names
+}
+
+// findCredential returns the credential with the given consumer key, or nil.
+func findCredential(credentials []Credential, consumerKey string) *Credential {
+  for i := range credentials {
+    if credentials[i].ConsumerKey == consumerKey {
+      return &credentials[i]
+    }
+  }
+  return nil
+}
+
+// newCredential returns the first credential in after whose key is not among
+// the credentials in before, or nil.
+func newCredential(before, after []Credential) *Credential {
+  for i := range after {
+    if findCredential(before, after[i].ConsumerKey) == nil {
+      return &after[i]
+    }
+  }
+  return nil
+}
+
+func (s *DeveloperAppsServiceOp) keyPath(appName, consumerKey string, elements ...string) string {
+  p := path.Join(s.ownerPath(), "apps", appName, "keys", consumerKey)
+  return path.Join(append([]string{p}, elements...)...)
+}
+
+// The payload for generating a new key on an app. Attributes are included so
+// that Edge does not clear them.
+type newKeyRequest struct {
+  Name          string      `json:"name"`
+  Attributes    Attributes  `json:"attributes,omitempty"`
+  ApiProducts   []string    `json:"apiProducts"`
+  KeyExpiresIn  *Timespan   `json:"keyExpiresIn,omitempty"`
+}
+
+// CreateKey generates a new consumer key and secret for an app, granting access
+// to the named API Products. If expiry is nil, the key never expires. The app
+// keeps its existing keys.
+func (s *DeveloperAppsServiceOp) CreateKey(appName string, apiProducts []string, expiry *Timespan) (*Credential, *Response, error) {
+  if len(apiProducts) == 0 {
+    return nil, nil, errors.New("must specify at least one API Product for the new key")
+  }
+  app, resp, e := s.Get(appName)
+  if e != nil {
+    return nil, resp, e
+  }
+  body := newKeyRequest{Name: app.Name, Attributes: app.Attributes, ApiProducts: apiProducts, KeyExpiresIn: expiry}
+  req, e := s.client.NewRequest("POST", path.Join(s.ownerPath(), "apps", appName), body)
+  if e != nil {
+    return nil, nil, e
+  }
+  req = markSensitive(req)
+  returnedApp := DeveloperApp{}
+  resp, e = s.client.Do(req, &returnedApp)
+  if e != nil {
+    return nil, resp, e
+  }
+  created := newCredential(app.Credentials, returnedApp.Credentials)
+  if created == nil {
+    return nil, resp, fmt.Errorf("no new key was issued for app %s", appName)
+  }
+  return created, resp, e
+}
+
+// GetKey retrieves one credential of an app.
+func (s *DeveloperAppsServiceOp) GetKey(appName, consumerKey string) (*Credential, *Response, error) {
+  req, e := s.client.NewRequest("GET", s.keyPath(appName, consumerKey), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  req = markSensitive(req)
+  returnedCredential := Credential{}
+  resp, e := s.client.Do(req, &returnedCredential)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedCredential, resp, e
+}
+
+// ImportKey adds a specific consumer key and secret to an app, for example one
+// migrated from another organization. Edge creates the key with no API
+// Products; any ApiProducts named in the credential are then added to it.
+func (s *DeveloperAppsServiceOp) ImportKey(appName string, cred Credential) (*Credential, *Response, error) {
+  if cred.ConsumerKey == "" || cred.ConsumerSecret == "" {
+    return nil, nil, errors.New("must specify the ConsumerKey and ConsumerSecret to import")
+  }
+  body := struct {
+    ConsumerKey     string  `json:"consumerKey"`
+    ConsumerSecret  string  `json:"consumerSecret"`
+  }{cred.ConsumerKey, cred.ConsumerSecret}
+  req, e := s.client.NewRequest("POST", s.keyPath(appName, "create"), body)
+  if e != nil {
+    return nil, nil, e
+  }
+  req = markSensitive(req)
+  returnedCredential := Credential{}
+  resp, e := s.client.Do(req, &returnedCredential)
+  if e != nil {
+    return nil, resp, e
+  }
+  if len(cred.ApiProducts) == 0 {
+    return &returnedCredential, resp, e
+  }
+  return s.AddKeyProducts(appName, cred.ConsumerKey, cred.ProductNames())
+}
+
+// AddKeyProducts associates more API Products to an existing consumer key.
+func (s *DeveloperAppsServiceOp) AddKeyProducts(appName, consumerKey string, apiProducts []string) (*Credential, *Response, error) {
+  body := struct {
+    ApiProducts []string `json:"apiProducts"`
+  }{apiProducts}
+  req, e := s.client.NewRequest("POST", s.keyPath(appName, consumerKey), body)
+  if e != nil {
+    return nil, nil, e
+  }
+  req = markSensitive(req)
+  returnedCredential := Credential{}
+  resp, e := s.client.Do(req, &returnedCredential)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedCredential, resp, e
+}
+
+// DeleteKey removes a consumer key from an app.
+func (s *DeveloperAppsServiceOp) DeleteKey(appName, consumerKey string) (*Credential, *Response, error) {
+  req, e := s.client.NewRequest("DELETE", s.keyPath(appName, consumerKey), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  req = markSensitive(req)
+  deletedCredential := Credential{}
+  resp, e := s.client.Do(req, &deletedCredential)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &deletedCredential, resp, e
+}
+
+func updateKeyStatus(s DeveloperAppsServiceOp, keyPath string, desiredStatus string) (*Response, error) {
+  // append the necessary query param
+  origURL, e := url.Parse(keyPath)
+  if e != nil {
+     return nil, e
+  }
+  q := origURL.Query()
+  q.Add("action", desiredStatus)
+  origURL.RawQuery = q.Encode()
+
+  req, e := s.client.NewRequest("POST", origURL.String(), nil)
+  if e != nil {
+    return nil, e
+  }
+  resp, e := s.client.Do(req, nil)
+  if e != nil {
+    return resp, e
+  }
+  return resp, e
+}
+
+// RevokeKey revokes a consumer key. Requests that present the key are rejected
+// until it is approved again.
+func (s *DeveloperAppsServiceOp) RevokeKey(appName, consumerKey string) (*Response, error) {
+  return updateKeyStatus(*s, s.keyPath(appName, consumerKey), "revoke")
+}
+
+// ApproveKey approves a consumer key.
+func (s *DeveloperAppsServiceOp) ApproveKey(appName, consumerKey string) (*Response, error) {
+  return updateKeyStatus(*s, s.keyPath(appName, consumerKey), "approve")
+}
+
+// RevokeKeyProduct revokes access to one API Product for a consumer key,
+// leaving its other API Products unchanged.
+func (s *DeveloperAppsServiceOp) RevokeKeyProduct(appName, consumerKey, productName string) (*Response, error) {
+  return updateKeyStatus(*s, s.keyPath(appName, consumerKey, "apiproducts", productName), "revoke")
+}
+
+// ApproveKeyProduct approves access to one API Product for a consumer key.
+func (s *DeveloperAppsServiceOp) ApproveKeyProduct(appName, consumerKey, productName string) (*Response, error) {
+  return updateKeyStatus(*s, s.keyPath(appName, consumerKey, "apiproducts", productName), "approve")
+}
@@ -0,0 +1,135 @@
+package apigee
+
+import (
+  "encoding/json"
+  "fmt"
+  "net/http"
+  "testing"
+  "time"
+)
+
+const (
+  appWithCredentialsJson1 = `{
+  "appId" : "0f5b5f1c-8c1e-4c0a-9a2e-2a3b4c5d6e7f",
+  "attributes" : [ { "name" : "DisplayName", "value" : "Flights" } ],
+  "credentials" : [ {
+    "apiProducts" : [ {
+      "apiproduct" : "flights-basic",
+      "status" : "approved"
+    }, {
+      "apiproduct" : "flights-premium",
+      "status" : "revoked"
+    } ],
+    "attributes" : [ ],
+    "consumerKey" : "key1",
+    "consumerSecret" : "secret1",
+    "expiresAt" : -1,
+    "issuedAt" : 1500000000000,
+    "scopes" : [ "read" ],
+    "status" : "approved"
+  }, {
+    "apiProducts" : [ {
+      "apiproduct" : "flights-basic",
+      "status" : "approved"
+    } ],
+    "attributes" : [ { "name" : "rotated", "value" : "true" } ],
+    "consumerKey" : "key2",
+    "consumerSecret" : "secret2",
+    "expiresAt" : 1924992000000,
+    "issuedAt" : 1600000000000,
+    "scopes" : [ ],
+    "status" : "approved"
+  } ],
+  "developerId" : "dev1",
+  "name" : "flights-app",
+  "status" : "approved"
+}`
+)
+
+func TestDeveloperAppCredentials_Unmarshal(t *testing.T) {
+  var app DeveloperApp
+  e := json.Unmarshal([]byte(appWithCredentialsJson1), &app)
+  if e != nil {
+    t.Errorf("while unmarshaling, error:\n%#v\n", e)
+    return
+  }
+  if len(app.Credentials) != 2 {
+    t.Errorf("got=%#v", app.Credentials)
+    return
+  }
+  first := app.Credentials[0]
+  if first.ConsumerKey != "key1" || first.ConsumerSecret != "secret1" || first.Status != "approved" {
+    t.Errorf("first: got=%#v", first)
+  }
+  if !first.NeverExpires() {
+    t.Errorf("first: expected no expiry, got=%v", first.ExpiresAt)
+  }
+  if first.IssuedAt.String() != "1500000000000" {
+    t.Errorf("first: issuedAt got=%v", first.IssuedAt)
+  }
+  if len(first.ApiProducts) != 2 || first.ApiProducts[1] != (CredentialProduct{"flights-premium", "revoked"}) {
+    t.Errorf("first: products got=%#v", first.ApiProducts)
+  }
+  second := app.Credentials[1]
+  if second.NeverExpires() || second.Attributes["rotated"] != "true" {
+    t.Errorf("second: got=%#v", second)
+  }
+  if !second.ExpiresBefore(time.Date(2031, 1, 2, 0, 0, 0, 0, time.UTC)) ||
+    second.ExpiresBefore(time.Date(2030, 12, 31, 0, 0, 0, 0, time.UTC)) {
+    t.Errorf("second: expiresAt got=%v", second.ExpiresAt.Time)
+  }
+}
+
+func TestNewCredential(t *testing.T) {
+  before := []Credential{{ConsumerKey: "key1"}}
+  after := []Credential{{ConsumerKey: "key1"}, {ConsumerKey: "key2"}}
+  got := newCredential(before, after)
+  if got == nil || got.ConsumerKey != "key2" {
+    t.Errorf("got=%#v", got)
+  }
+  if got := newCredential(after, after); got != nil {
+    t.Errorf("expected nil, got=%#v", got)
+  }
+}
+
+func TestKeyPath(t *testing.T) {
+  s := &DeveloperAppsServiceOp{developerId: "dev@example.com"}
+  testCases := []struct {
+    got      string
+    expected string
+  }{
+    {s.keyPath("app1", "key1"), "developers/dev@example.com/apps/app1/keys/key1"},
+    {s.keyPath("app1", "create"), "developers/dev@example.com/apps/app1/keys/create"},
+    {s.keyPath("app1", "key1", "apiproducts", "p1"), "developers/dev@example.com/apps/app1/keys/key1/apiproducts/p1"},
+  }
+  for i, tc := range testCases {
+    if tc.got != tc.expected {
+      t.Errorf("case %d: got=%q, expected=%q", i, tc.got, tc.expected)
+    }
+  }
+}
+
+func TestDeveloperApps_Sensitive(t *testing.T) {
+  client, stop := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+    fmt.Fprint(w, appWithCredentialsJson1)
+  })
+  defer stop()
+  calls := map[string]bool{}
+  client.OnRequestCompleted(func(req *http.Request, resp *http.Response) {
+    calls[req.Method+" "+req.URL.Path] = isSensitive(req)
+  })
+  for _, apps := range []DeveloperAppsService{client.Developers.Apps("dev@example.com"), client.Companies.Apps("acme")} {
+    apps.Create(DeveloperApp{Name: "flights-app"})
+    apps.Get("flights-app")
+    apps.Update(DeveloperApp{Name: "flights-app"})
+    apps.Delete("flights-app")
+  }
+  if len(calls) != 8 {
+    t.Errorf("got=%v", calls)
+  }
+  for call, sensitive := range calls {
+    if !sensitive {
+      t.Errorf("%s: not marked sensitive", call)
+    }
+  }
+}
@@ -88,6 +88,25 @@ type RevisionDeployment struct {
   Number        Revision      `json:"name,omitempty"`
   State         string        `json:"state,omitempty"`
   Servers       []ApigeeServer  `json:"server,omitempty"`
+  Configuration *DeploymentConfiguration `json:"configuration,omitempty"`
+  // the basepath conflicts found before deploying, under WarnOnConflicts
+  Conflicts     []BasePathConflict `json:"-"`
+}
+
+// DeploymentConfiguration holds the configuration of a deployed revision, as
+// reported by Edge. For API Proxies, this includes the basepath at which the
+// revision was deployed.
+type DeploymentConfiguration struct {
+  BasePath      string        `json:"basePath,omitempty"`
+}
+
+// BasePath returns the basepath at which the revision was deployed, or the
+// empty string if Edge did not report one.
+func (d RevisionDeployment) BasePath() string {
+  if d.Configuration == nil {
+    return ""
+  }
+  return d.Configuration.BasePath
 }
 
 
@@ -271,7 +290,9 @@ func (s *Deployable) Import(client *ApigeeClient, uriPathElement, assetName, sou
   return &returnedRevision, resp, e
 }
 
-func (s *Deployable) Export(client *ApigeeClient, uriPathElement, assetName string, rev Revision) (string, *Response, error) {
+// exportBundle retrieves the bundle for a revision of an API Proxy or
+// SharedFlow, and writes the zip content to w.
+func exportBundle(client *ApigeeClient, uriPathElement, assetName string, rev Revision, w io.Writer) (*Response, error) {
   // curl -u USER:PASSWORD \
   //  http://MGMTSERVER/v1/o/ORGNAME/apis/APINAME/revisions/REVNUMBER?format=bundle > bundle.zip
 
@@ -280,7 +301,7 @@ func (s *Deployable) Export(client *ApigeeClient, uriPathElement, assetName stri
   // append the required query param
   origURL, err := url.Parse(path)
   if err != nil {
-		return "", nil, err
+		return nil, err
   }
   q := origURL.Query()
   q.Add("format", "bundle")
@@ -289,10 +310,13 @@ func (s *Deployable) Export(client *ApigeeClient, uriPathElement, assetName stri
 
   req, e := client.NewRequest("GET", path, nil)
   if e != nil {
-    return "", nil, e
+    return nil, e
   }
   req.Header.Del("Accept")
+  return client.Do(req, w)
+}
 
+func (s *Deployable) Export(client *ApigeeClient, uriPathElement, assetName string, rev Revision) (string, *Response, error) {
 	var assetType string;
 	if uriPathElement == "apis" {
 		assetType = "apiproxy"
@@ -311,14 +335,97 @@ func (s *Deployable) Export(client *ApigeeClient, uriPathElement, assetName stri
     return "", nil, e
   }
 
-  resp, e := client.Do(req, out)
+  resp, e := exportBundle(client, uriPathElement, assetName, rev, out)
   if e != nil {
+    out.Close()
     return "", resp, e
   }
   out.Close()
   return filename, resp, e
 }
 
+func (s *Deployable) GetRevision(client *ApigeeClient, uriPathElement, assetName string, rev Revision) (*DeployableRevision, *Response, error) {
+  path := path.Join(uriPathElement, assetName, "revisions", fmt.Sprintf("%d",rev))
+  req, e := client.NewRequest("GET", path, nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedRevision := DeployableRevision{}
+  resp, e := client.Do(req, &returnedRevision)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedRevision, resp, e
+}
+
+// latestRevision returns the highest revision number in the list, or 0 if the
+// list is empty.
+func latestRevision(revisions []Revision) Revision {
+  var latest Revision
+  for _, rev := range revisions {
+    if rev > latest {
+      latest = rev
+    }
+  }
+  return latest
+}
+
+// isNotFound returns true if the error is an ErrorResponse carrying a 404 status.
+func isNotFound(e error) bool {
+  errorResponse, ok := e.(*ErrorResponse)
+  return ok && errorResponse.Response != nil && errorResponse.Response.StatusCode == 404
+}
+
+// ImportIfChanged imports the bundle only if its fingerprint differs from that
+// of the latest existing revision. The fingerprint of the latest revision is
+// taken from its description, if it contains one, else computed by exporting
+// that revision. When the content is unchanged, nothing is uploaded, and the
+// returned DeployableRevision describes the existing latest revision.
+func (s *Deployable) ImportIfChanged(client *ApigeeClient, uriPathElement, assetName, source string) (*DeployableRevision, *Response, error) {
+  if assetName == "" {
+    info, e := os.Stat(source)
+    if e != nil {
+      return nil, nil, e
+    }
+    if !info.IsDir() {
+      return nil, nil, errors.New("must specify a name when importing from a zipfile")
+    }
+    assetName = filepath.Base(source)
+  }
+  fingerprint, e := BundleFingerprint(source)
+  if e != nil {
+    return nil, nil, e
+  }
+
+  asset, resp, e := s.Get(client, uriPathElement, assetName)
+  if e != nil {
+    if isNotFound(e) {
+      return s.Import(client, uriPathElement, assetName, source)
+    }
+    return nil, resp, e
+  }
+  latest := latestRevision(asset.Revisions)
+  if latest == 0 {
+    return s.Import(client, uriPathElement, assetName, source)
+  }
+
+  existing, resp, e := s.GetRevision(client, uriPathElement, assetName, latest)
+  if e != nil {
+    return nil, resp, e
+  }
+  existingFingerprint := fingerprintFromDescription(existing.Description)
+  if existingFingerprint == "" {
+    existingFingerprint, resp, e = fingerprintRevision(client, uriPathElement, assetName, latest)
+    if e != nil {
+      return nil, resp, e
+    }
+  }
+  if existingFingerprint == fingerprint {
+    return existing, resp, nil
+  }
+  return s.Import(client, uriPathElement, assetName, source)
+}
+
 func (s *Deployable) DeleteRevision(client *ApigeeClient, uriPathElement, assetName string, rev Revision) (*DeployableRevision, *Response, error) {
   path := path.Join(uriPathElement, assetName, "revisions", fmt.Sprintf("%d",rev))
   req, e := client.NewRequest("DELETE", path, nil)
@@ -362,6 +469,14 @@ func (s *Deployable) Undeploy(client *ApigeeClient, uriPathElement, assetName, e
 
 
 func (s *Deployable) Deploy(client *ApigeeClient, uriPathElement, assetName, basepath, env string, rev Revision) (*RevisionDeployment, *Response, error) {
+  var conflicts []BasePathConflict
+  if uriPathElement == apiUriPathElement && client.Options.BasePathConflicts != IgnoreConflicts {
+    var e error
+    conflicts, e = checkDeployConflicts(client, assetName, basepath, env, rev)
+    if e != nil {
+      return nil, nil, e
+    }
+  }
   path := path.Join(uriPathElement, assetName, "revisions", fmt.Sprintf("%d",rev), "deployments")
   // append the query params
   origURL, err := url.Parse(path)
@@ -389,6 +504,10 @@ func (s *Deployable) Deploy(client *ApigeeClient, uriPathElement, assetName, bas
   if e != nil {
     return nil, resp, e
   }
+  if client.history != nil {
+    client.history.record(uriPathElement, assetName, env, rev)
+  }
+  deployment.Conflicts = conflicts
   return &deployment, resp, e
 }
 
@@ -17,11 +17,28 @@ type DeveloperAppsService interface {
   List() ([]string, *Response, error)
   Get( string) (*DeveloperApp, *Response, error)
   Update(DeveloperApp) (*DeveloperApp, *Response, error)
+  CreateKey(string, []string, *Timespan) (*Credential, *Response, error)
+  GetKey(string, string) (*Credential, *Response, error)
+  ImportKey(string, Credential) (*Credential, *Response, error)
+  AddKeyProducts(string, string, []string) (*Credential, *Response, error)
+  DeleteKey(string, string) (*Credential, *Response, error)
+  RevokeKey(string, string) (*Response, error)
+  ApproveKey(string, string) (*Response, error)
+  RevokeKeyProduct(string, string, string) (*Response, error)
+  ApproveKeyProduct(string, string, string) (*Response, error)
+  RotateKey(string, string, *Timespan) (*KeyRotation, error)
+  RetireExpiredKeys(string, bool) ([]RetiredKey, error)
+  GetAttribute(string, string) (*Attribute, *Response, error)
+  SetAttribute(string, Attribute) (*Attribute, *Response, error)
+  DeleteAttribute(string, string) (*Attribute, *Response, error)
+  ReplaceAttributes(string, Attributes) (Attributes, *Response, error)
 }
 
 type DeveloperAppsServiceOp struct {
   client *ApigeeClient
   developerId string
+  // set instead of developerId, for the apps of a company
+  companyName string
 }
 
 var _ DeveloperAppsService = &DeveloperAppsServiceOp{}
@@ -36,17 +53,43 @@ type DeveloperApp struct {
   DeveloperId      string      `json:"developerId,omitempty"`
   Scopes           []string    `json:"scopes,omitempty"`
   Status           string      `json:"status,omitempty"`
+  Credentials      []Credential `json:"credentials,omitempty"`
+  CompanyName      string      `json:"companyName,omitempty"`
+  // fields returned by Edge that are not modeled above; sent back on Update
+  Extra            ExtraFields `json:"-"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Fields that
+// DeveloperApp does not model are kept in Extra.
+func (app *DeveloperApp) UnmarshalJSON(data []byte) error {
+  type plain DeveloperApp
+  return unmarshalWithExtra(data, (*plain)(app), &app.Extra)
+}
+
+// MarshalJSON implements the json.Marshaler interface, including the Extra fields.
+func (app DeveloperApp) MarshalJSON() ([]byte, error) {
+  type plain DeveloperApp
+  return marshalWithExtra(plain(app), app.Extra)
+}
+
+// ownerPath returns the path of the developer or company that owns the apps.
+func (s *DeveloperAppsServiceOp) ownerPath() string {
+  if s.companyName != "" {
+    return path.Join(companiesPath, s.companyName)
+  }
+  return path.Join(developersPath, s.developerId)
 }
 
 func (s *DeveloperAppsServiceOp) Create(app DeveloperApp) (*DeveloperApp, *Response, error) {
 	if (app.Name == "") {
 		return nil, nil, errors.New("cannot create a developerapp with no name")
 	}
-	appsPath := path.Join(developersPath, s.developerId, "apps")
+	appsPath := path.Join(s.ownerPath(), "apps")
   req, e := s.client.NewRequest("POST", appsPath, app)
   if e != nil {
     return nil, nil, e
   }
+  req = markSensitive(req)
   returnedApp := DeveloperApp{}
   resp, e := s.client.Do(req, &returnedApp)
   if e != nil {
@@ -56,11 +99,12 @@ func (s *DeveloperAppsServiceOp) Create(app DeveloperApp) (*DeveloperApp, *Respo
 }
 
 func (s *DeveloperAppsServiceOp) Delete(appName string) (*DeveloperApp, *Response, error) {
-  path := path.Join(developersPath, s.developerId, "apps", appName)
+  path := path.Join(s.ownerPath(), "apps", appName)
   req, e := s.client.NewRequest("DELETE", path, nil)
   if e != nil {
     return nil, nil, e
   }
+  req = markSensitive(req)
   deletedApp := DeveloperApp{}
   resp, e := s.client.Do(req, &deletedApp)
   if e != nil {
@@ -72,7 +116,7 @@ func (s *DeveloperAppsServiceOp) Delete(appName string) (*DeveloperApp, *Respons
 
 func updateAppStatus (s DeveloperAppsServiceOp, appName string, desiredStatus string) (*Response, error) {
 
-  appPath := path.Join(developersPath, s.developerId, "apps", appName)
+  appPath := path.Join(s.ownerPath(), "apps", appName)
 
   // append the necessary query param
   origURL, e := url.Parse(appPath)
@@ -104,7 +148,7 @@ func (s *DeveloperAppsServiceOp) Approve(appName string) (*Response, error) {
 }
 
 func (s *DeveloperAppsServiceOp) List() ([]string, *Response, error) {
-  appsPath := path.Join(developersPath, s.developerId, "apps")
+  appsPath := path.Join(s.ownerPath(), "apps")
   req, e := s.client.NewRequest("GET", appsPath, nil)
   if e != nil {
     return nil, nil, e
@@ -118,11 +162,13 @@ func (s *DeveloperAppsServiceOp) List() ([]string, *Response, error) {
 }
 
 func (s *DeveloperAppsServiceOp) Get(appName string) (*DeveloperApp, *Response, error) {
-  appPath := path.Join(developersPath, s.developerId, "apps", appName)
+  appPath := path.Join(s.ownerPath(), "apps", appName)
   req, e := s.client.NewRequest("GET", appPath, nil)
   if e != nil {
     return nil, nil, e
   }
+  // the app returned carries the consumer secrets of its keys
+  req = markSensitive(req)
   returnedApp := DeveloperApp{}
   resp, e := s.client.Do(req, &returnedApp)
   if e != nil {
@@ -135,12 +181,13 @@ func (s *DeveloperAppsServiceOp) Update(app DeveloperApp) (*DeveloperApp, *Respo
 	if app.Name == "" {
     return nil, nil, errors.New("missing the Name of the App to update")
 	}
-	appPath := path.Join(developersPath, s.developerId, "apps", app.Name)
+	appPath := path.Join(s.ownerPath(), "apps", app.Name)
 
   req, e := s.client.NewRequest("POST", appPath, app)
   if e != nil {
     return nil, nil, e
   }
+  req = markSensitive(req)
   returnedApp := DeveloperApp{}
   resp, e := s.client.Do(req, &returnedApp)
   if e != nil {
@@ -0,0 +1,164 @@
+package apigee
+
+import (
+  "strings"
+  "time"
+)
+
+// developersPageSize is the number of developers requested per page by Query.
+var developersPageSize = 1000
+
+// DeveloperQuery holds criteria for finding developers. All the criteria that
+// are set must match. The zero value matches every developer.
+type DeveloperQuery struct {
+  // "active" or "inactive"
+  Status          string
+  // the name of a company the developer belongs to
+  Company         string
+  // attributes the developer must have, with these values
+  Attributes      Attributes
+  // the name of an app the developer owns
+  App             string
+  // emails or ids of developers
+  Ids             []string
+  // include developers that belong to companies; Edge omits them by default
+  IncludeCompany  bool
+  CreatedAfter    time.Time
+  CreatedBefore   time.Time
+  ModifiedAfter   time.Time
+  ModifiedBefore  time.Time
+}
+
+// The query parameters Edge supports when listing developers.
+type developerListParams struct {
+  Expand          bool    `url:"expand"`
+  App             string  `url:"app,omitempty"`
+  Ids             string  `url:"ids,omitempty"`
+  IncludeCompany  bool    `url:"includeCompany,omitempty"`
+  Count           int     `url:"count,omitempty"`
+  StartKey        string  `url:"startKey,omitempty"`
+}
+
+// This is just a wrapper struct to aid in de-serialization of expanded developer lists.
+type developersRoot struct {
+  Developers []Developer `json:"developer"`
+}
+
+// serverParams returns the parameters for the criteria that Edge can apply itself.
+func (q DeveloperQuery) serverParams() developerListParams {
+  return developerListParams{
+    Expand: true,
+    App: q.App,
+    Ids: strings.Join(q.Ids, ","),
+    // company members are only listed when Edge is asked to include them
+    IncludeCompany: q.IncludeCompany || q.Company != "",
+  }
+}
+
+func inTimeRange(ts Timestamp, after, before time.Time) bool {
+  if !after.IsZero() && !ts.Time.After(after) {
+    return false
+  }
+  if !before.IsZero() && !ts.Time.Before(before) {
+    return false
+  }
+  return true
+}
+
+func containsString(list []string, s string) bool {
+  for _, item := range list {
+    if item == s {
+      return true
+    }
+  }
+  return false
+}
+
+// Matches returns true if the developer meets all the criteria of the query.
+func (q DeveloperQuery) Matches(dev Developer) bool {
+  if q.Status != "" && dev.Status != q.Status {
+    return false
+  }
+  if q.Company != "" && !containsString(dev.Companies, q.Company) {
+    return false
+  }
+  for name, value := range q.Attributes {
+    if v, ok := dev.Attributes[name]; !ok || v != value {
+      return false
+    }
+  }
+  if q.App != "" && !containsString(dev.Apps, q.App) {
+    return false
+  }
+  if len(q.Ids) > 0 && !containsString(q.Ids, dev.Email) && !containsString(q.Ids, dev.Id) {
+    return false
+  }
+  return inTimeRange(dev.CreatedAt, q.CreatedAfter, q.CreatedBefore) &&
+    inTimeRange(dev.LastModifiedAt, q.ModifiedAfter, q.ModifiedBefore)
+}
+
+// listExpanded retrieves all the developers, with their details, that match
+// the parameters, paging through the results.
+func (s *DevelopersServiceOp) listExpanded(params developerListParams) ([]Developer, error) {
+  paged := params.App == "" && params.Ids == ""
+  if paged {
+    params.Count = developersPageSize
+  }
+  developers := []Developer{}
+  for {
+    p, e := addOptions(developersPath, params)
+    if e != nil {
+      return nil, e
+    }
+    req, e := s.client.NewRequest("GET", p, nil)
+    if e != nil {
+      return nil, e
+    }
+    root := developersRoot{}
+    _, e = s.client.Do(req, &root)
+    if e != nil {
+      return nil, e
+    }
+    page := root.Developers
+    if params.StartKey != "" && len(page) > 0 && page[0].Email == params.StartKey {
+      // the start key is included in the results, and was on the previous page
+      page = page[1:]
+    }
+    developers = append(developers, page...)
+    if !paged || len(page) == 0 || len(root.Developers) < developersPageSize {
+      return developers, nil
+    }
+    params.StartKey = root.Developers[len(root.Developers)-1].Email
+  }
+}
+
+func isBadRequest(e error) bool {
+  errorResponse, ok := e.(*ErrorResponse)
+  return ok && errorResponse.Response != nil && errorResponse.Response.StatusCode == 400
+}
+
+// Query finds the developers that match the criteria. Edge applies the App,
+// Ids and IncludeCompany criteria itself; if it rejects them, Query lists all
+// developers instead. Either way, every criterion is then checked against the
+// expanded developers, so the results are the same.
+func (s *DevelopersServiceOp) Query(q DeveloperQuery) ([]Developer, error) {
+  params := q.serverParams()
+  fallback := developerListParams{Expand: true, IncludeCompany: params.IncludeCompany}
+  developers, e := s.listExpanded(params)
+  if e != nil {
+    if !isBadRequest(e) || params == fallback {
+      return nil, e
+    }
+    developers, e = s.listExpanded(fallback)
+    if e != nil {
+      return nil, e
+    }
+  }
+  matched := []Developer{}
+  for _, dev := range developers {
+    if q.Matches(dev) {
+      matched = append(matched, dev)
+    }
+  }
+  return matched, nil
+}
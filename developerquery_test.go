@@ -0,0 +1,201 @@
+package apigee
+
+import (
+  "encoding/json"
+  "fmt"
+  "net/http"
+  "strings"
+  "testing"
+  "time"
+)
+
+const (
+  expandedDevelopersJson1 = `{
+  "developer" : [ {
+    "apps" : [ "flights-app" ],
+    "attributes" : [ { "name" : "tier", "value" : "gold" } ],
+    "companies" : [ "acme" ],
+    "createdAt" : 1500000000000,
+    "createdBy" : "admin@example.com",
+    "developerId" : "dev1",
+    "email" : "alice@example.com",
+    "firstName" : "Alice",
+    "lastModifiedAt" : 1600000000000,
+    "lastModifiedBy" : "admin@example.com",
+    "lastName" : "Example",
+    "status" : "active",
+    "userName" : "alice"
+  }, {
+    "apps" : [ ],
+    "attributes" : [ { "name" : "tier", "value" : "silver" } ],
+    "companies" : [ ],
+    "createdAt" : 1700000000000,
+    "email" : "bob@example.com",
+    "lastModifiedAt" : 1700000000000,
+    "status" : "inactive",
+    "userName" : "bob"
+  } ]
+}`
+)
+
+func TestDevelopersRoot_Unmarshal(t *testing.T) {
+  var root developersRoot
+  e := json.Unmarshal([]byte(expandedDevelopersJson1), &root)
+  if e != nil {
+    t.Errorf("while unmarshaling, error:\n%#v\n", e)
+    return
+  }
+  if len(root.Developers) != 2 {
+    t.Errorf("got=%#v", root.Developers)
+    return
+  }
+  alice := root.Developers[0]
+  if alice.CreatedAt.String() != "1500000000000" || alice.LastModifiedAt.String() != "1600000000000" ||
+    alice.CreatedBy != "admin@example.com" {
+    t.Errorf("timestamps: got=%#v", alice)
+  }
+}
+
+func TestDeveloperQuery_Matches(t *testing.T) {
+  var root developersRoot
+  e := json.Unmarshal([]byte(expandedDevelopersJson1), &root)
+  if e != nil {
+    t.Errorf("while unmarshaling, error:\n%#v\n", e)
+    return
+  }
+  // 1500000000000 is 2017-07-14, 1600000000000 is 2020-09-13, 1700000000000 is 2023-11-14
+  testCases := []struct {
+    query    DeveloperQuery
+    expected []string
+  }{
+    {DeveloperQuery{}, []string{"alice@example.com", "bob@example.com"}},
+    {DeveloperQuery{Status: "active"}, []string{"alice@example.com"}},
+    {DeveloperQuery{Company: "acme"}, []string{"alice@example.com"}},
+    {DeveloperQuery{Attributes: Attributes{"tier": "silver"}}, []string{"bob@example.com"}},
+    {DeveloperQuery{App: "flights-app"}, []string{"alice@example.com"}},
+    {DeveloperQuery{Ids: []string{"bob@example.com"}}, []string{"bob@example.com"}},
+    {DeveloperQuery{CreatedAfter: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}, []string{"bob@example.com"}},
+    {DeveloperQuery{CreatedBefore: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}, []string{"alice@example.com"}},
+    {DeveloperQuery{ModifiedAfter: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+      ModifiedBefore: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}, []string{"alice@example.com"}},
+    {DeveloperQuery{Status: "active", Attributes: Attributes{"tier": "silver"}}, []string{}},
+  }
+  for i, tc := range testCases {
+    got := []string{}
+    for _, dev := range root.Developers {
+      if tc.query.Matches(dev) {
+        got = append(got, dev.Email)
+      }
+    }
+    if len(got) != len(tc.expected) {
+      t.Errorf("case %d: got=%v, expected=%v", i, got, tc.expected)
+      continue
+    }
+    for j := range got {
+      if got[j] != tc.expected[j] {
+        t.Errorf("case %d: got=%v, expected=%v", i, got, tc.expected)
+      }
+    }
+  }
+}
+
+func TestDeveloperQuery_ServerParams(t *testing.T) {
+  testCases := []struct {
+    desc      string
+    q         DeveloperQuery
+    expected  string
+  }{
+    {"app and ids", DeveloperQuery{App: "flights-app", Ids: []string{"a@example.com", "b@example.com"}, IncludeCompany: true, Status: "active"},
+      "developers?app=flights-app&expand=true&ids=a%40example.com%2Cb%40example.com&includeCompany=true"},
+    {"company", DeveloperQuery{Company: "acme"}, "developers?expand=true&includeCompany=true"},
+    {"none", DeveloperQuery{Status: "active"}, "developers?expand=true"},
+  }
+  for _, tc := range testCases {
+    got, e := addOptions(developersPath, tc.q.serverParams())
+    if e != nil {
+      t.Errorf("%s: while adding options, error:\n%#v\n", tc.desc, e)
+      continue
+    }
+    if got != tc.expected {
+      t.Errorf("%s: got=%q, expected=%q", tc.desc, got, tc.expected)
+    }
+  }
+}
+
+// developersPage renders a page of expanded developers, each owning one app
+// named after the developer.
+func developersPage(emails ...string) string {
+  devs := []string{}
+  for _, email := range emails {
+    devs = append(devs, fmt.Sprintf(`{"email":%q,"apps":[%q]}`, email, strings.Split(email, "@")[0]+"-app"))
+  }
+  return `{"developer":[` + strings.Join(devs, ",") + `]}`
+}
+
+func TestDevelopersQuery_Paging(t *testing.T) {
+  saved := developersPageSize
+  developersPageSize = 2
+  defer func() { developersPageSize = saved }()
+
+  // Edge includes the start key as the first developer of the next page. The
+  // page starting at d3 is full and holds the last developer, so it takes one
+  // more request, which adds nothing, to end the loop.
+  pages := map[string]string{
+    "": developersPage("d1@example.com", "d2@example.com"),
+    "d2@example.com": developersPage("d2@example.com", "d3@example.com"),
+    "d3@example.com": developersPage("d3@example.com", "d4@example.com"),
+    "d4@example.com": developersPage("d4@example.com"),
+  }
+  requests := []string{}
+  client, stop := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+    startKey := r.URL.Query().Get("startKey")
+    requests = append(requests, startKey)
+    page, ok := pages[startKey]
+    if !ok || r.URL.Query().Get("count") != "2" {
+      http.Error(w, `{"code":"unexpected"}`, http.StatusBadRequest)
+      return
+    }
+    fmt.Fprint(w, page)
+  })
+  defer stop()
+  got, e := client.Developers.Query(DeveloperQuery{})
+  if e != nil {
+    t.Errorf("unexpected error: %v", e)
+    return
+  }
+  emails := []string{}
+  for _, dev := range got {
+    emails = append(emails, dev.Email)
+  }
+  expected := "d1@example.com,d2@example.com,d3@example.com,d4@example.com"
+  if strings.Join(emails, ",") != expected {
+    t.Errorf("got=%v, expected=%s", emails, expected)
+  }
+  if len(requests) != 4 {
+    t.Errorf("requests: got=%q, expected 4", requests)
+  }
+}
+
+func TestDevelopersQuery_Fallback(t *testing.T) {
+  requests := []string{}
+  client, stop := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+    requests = append(requests, r.URL.RawQuery)
+    if r.URL.Query().Get("app") != "" {
+      http.Error(w, `{"code":"developer.service.InvalidQueryParameter"}`, http.StatusBadRequest)
+      return
+    }
+    fmt.Fprint(w, developersPage("d1@example.com", "d2@example.com"))
+  })
+  defer stop()
+  got, e := client.Developers.Query(DeveloperQuery{App: "d2-app"})
+  if e != nil {
+    t.Errorf("unexpected error: %v", e)
+    return
+  }
+  if len(got) != 1 || got[0].Email != "d2@example.com" {
+    t.Errorf("got=%#v", got)
+  }
+  if len(requests) != 2 || strings.Contains(requests[1], "app=") {
+    t.Errorf("requests: got=%q", requests)
+  }
+}
@@ -19,6 +19,12 @@ type DevelopersService interface {
   Revoke(string) (*Response, error)
   Approve(string) (*Response, error)
   Apps(string) (DeveloperAppsService)
+  ExpiringKeys(*Timespan) ([]ExpiringKey, error)
+  GetAttribute(string, string) (*Attribute, *Response, error)
+  SetAttribute(string, Attribute) (*Attribute, *Response, error)
+  DeleteAttribute(string, string) (*Attribute, *Response, error)
+  ReplaceAttributes(string, Attributes) (Attributes, *Response, error)
+  Query(DeveloperQuery) ([]Developer, error)
 }
 
 type DevelopersServiceOp struct {
@@ -39,6 +45,25 @@ type Developer struct {
   Email            string      `json:"email,omitempty"`
   Id               string      `json:"uuid,omitempty"`
   Apps             []string    `json:"apps,omitempty"`
+  CreatedBy        string      `json:"createdBy,omitempty"`
+  CreatedAt        Timestamp   `json:"createdAt,omitempty"`
+  LastModifiedBy   string      `json:"lastModifiedBy,omitempty"`
+  LastModifiedAt   Timestamp   `json:"lastModifiedAt,omitempty"`
+  // fields returned by Edge that are not modeled above; sent back on Update
+  Extra            ExtraFields `json:"-"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Fields that
+// Developer does not model are kept in Extra.
+func (dev *Developer) UnmarshalJSON(data []byte) error {
+  type plain Developer
+  return unmarshalWithExtra(data, (*plain)(dev), &dev.Extra)
+}
+
+// MarshalJSON implements the json.Marshaler interface, including the Extra fields.
+func (dev Developer) MarshalJSON() ([]byte, error) {
+  type plain Developer
+  return marshalWithExtra(plain(dev), dev.Extra)
 }
 
 func (s *DevelopersServiceOp) Update(dev Developer) (*Developer, *Response, error) {
@@ -2,6 +2,7 @@ package apigee
 
 import (
   "path"
+  "net/url"
 )
 
 const environmentsPath = "environments"
@@ -11,6 +12,8 @@ const environmentsPath = "environments"
 type EnvironmentsService interface {
   List() ([]string, *Response, error)
   Get(string) (*Environment, *Response, error)
+  GetDeployments(string) ([]DeployedRevision, error)
+  GetAllDeployments() ([]DeployedRevision, error)
 }
 
 type EnvironmentsServiceOp struct {
@@ -27,6 +30,21 @@ type Environment struct {
   LastModifiedBy  string      `json:"lastModifiedBy,omitempty"`
   LastModifiedAt  Timestamp   `json:"lastModifiedAt,omitempty"`
   Properties      PropertyWrapper `json:"properties,omitempty"`
+  // fields returned by Edge that are not modeled above; kept when marshaled
+  Extra           ExtraFields `json:"-"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Fields that
+// Environment does not model are kept in Extra.
+func (env *Environment) UnmarshalJSON(data []byte) error {
+  type plain Environment
+  return unmarshalWithExtra(data, (*plain)(env), &env.Extra)
+}
+
+// MarshalJSON implements the json.Marshaler interface, including the Extra fields.
+func (env Environment) MarshalJSON() ([]byte, error) {
+  type plain Environment
+  return marshalWithExtra(plain(env), env.Extra)
 }
 
 
@@ -59,3 +77,104 @@ func (s *EnvironmentsServiceOp) Get(env string) (*Environment, *Response, error)
   }
   return &returnedEnv, resp, e
 }
+
+// DeployedRevision describes one revision of an API Proxy or SharedFlow that is
+// deployed in an environment. The Revision holds the state, the per-server
+// status, and for API Proxies, the basepath.
+type DeployedRevision struct {
+  Env       string
+  // "apis" or "sharedflows"
+  Type      string
+  Name      string
+  Revision  RevisionDeployment
+}
+
+// The deployments of an environment, as Edge reports them. Each entry has the
+// same form as an EnvironmentDeployment, except that the name is the name of the
+// API Proxy or SharedFlow rather than of an environment.
+type environmentDeploymentsRoot struct {
+  Name         string                    `json:"name,omitempty"`
+  Proxies      []EnvironmentDeployment   `json:"aPIProxy,omitempty"`
+  SharedFlows  []EnvironmentDeployment   `json:"sharedFlow,omitempty"`
+}
+
+type organizationDeploymentsRoot struct {
+  Environments []environmentDeploymentsRoot `json:"environment,omitempty"`
+}
+
+func (root environmentDeploymentsRoot) flatten(env, assetType string) []DeployedRevision {
+  if root.Name != "" {
+    env = root.Name
+  }
+  deployed := []DeployedRevision{}
+  for _, assets := range [][]EnvironmentDeployment{root.Proxies, root.SharedFlows} {
+    for _, asset := range assets {
+      for _, rev := range asset.Revision {
+        deployed = append(deployed, DeployedRevision{Env: env, Type: assetType, Name: asset.Name, Revision: rev})
+      }
+    }
+  }
+  return deployed
+}
+
+func sharedFlowsQuery(p string, sharedFlows bool) (string, error) {
+  if !sharedFlows {
+    return p, nil
+  }
+  origURL, e := url.Parse(p)
+  if e != nil {
+    return "", e
+  }
+  q := origURL.Query()
+  q.Add("sharedFlows", "true")
+  origURL.RawQuery = q.Encode()
+  return origURL.String(), nil
+}
+
+// GetDeployments retrieves every revision of every API Proxy and SharedFlow
+// deployed in an environment.
+func (s *EnvironmentsServiceOp) GetDeployments(env string) ([]DeployedRevision, error) {
+  deployed := []DeployedRevision{}
+  for _, assetType := range []string{apiUriPathElement, sfUriPathElement} {
+    p, e := sharedFlowsQuery(path.Join("e", env, "deployments"), assetType == sfUriPathElement)
+    if e != nil {
+      return nil, e
+    }
+    req, e := s.client.NewRequest("GET", p, nil)
+    if e != nil {
+      return nil, e
+    }
+    root := environmentDeploymentsRoot{}
+    _, e = s.client.Do(req, &root)
+    if e != nil {
+      return nil, e
+    }
+    deployed = append(deployed, root.flatten(env, assetType)...)
+  }
+  return deployed, nil
+}
+
+// GetAllDeployments retrieves every revision of every API Proxy and SharedFlow
+// deployed in any environment of the organization.
+func (s *EnvironmentsServiceOp) GetAllDeployments() ([]DeployedRevision, error) {
+  deployed := []DeployedRevision{}
+  for _, assetType := range []string{apiUriPathElement, sfUriPathElement} {
+    p, e := sharedFlowsQuery("deployments", assetType == sfUriPathElement)
+    if e != nil {
+      return nil, e
+    }
+    req, e := s.client.NewRequest("GET", p, nil)
+    if e != nil {
+      return nil, e
+    }
+    root := organizationDeploymentsRoot{}
+    _, e = s.client.Do(req, &root)
+    if e != nil {
+      return nil, e
+    }
+    for _, envRoot := range root.Environments {
+      deployed = append(deployed, envRoot.flatten("", assetType)...)
+    }
+  }
+  return deployed, nil
+}
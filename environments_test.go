@@ -1,6 +1,8 @@
 package apigee
 
 import (
+  "encoding/json"
+  "fmt"
   "testing"
 )
 
@@ -20,3 +22,81 @@ func TestEnvList(t *testing.T) {
     return
   }
 }
+
+const (
+  envDeploymentsJson1 = `{
+  "aPIProxy" : [ {
+    "name" : "flights",
+    "revision" : [ {
+      "configuration" : { "basePath" : "/v1/flights", "steps" : [ ] },
+      "name" : "4",
+      "server" : [ { "status" : "deployed", "type" : [ "message-processor" ], "uUID" : "a1" } ],
+      "state" : "deployed"
+    } ]
+  }, {
+    "name" : "hotels",
+    "revision" : [ {
+      "configuration" : { "basePath" : "/", "steps" : [ ] },
+      "name" : "2",
+      "server" : [ ],
+      "state" : "deployed"
+    } ]
+  } ],
+  "name" : "test",
+  "organization" : "cheeso"
+}`
+
+  orgDeploymentsJson1 = `{
+  "environment" : [ {
+    "aPIProxy" : [ {
+      "name" : "flights",
+      "revision" : [ { "name" : "4", "state" : "deployed" } ]
+    } ],
+    "name" : "test"
+  }, {
+    "aPIProxy" : [ {
+      "name" : "flights",
+      "revision" : [ { "name" : "3", "state" : "deployed" } ]
+    } ],
+    "name" : "prod"
+  } ],
+  "name" : "cheeso"
+}`
+)
+
+func TestEnvironmentDeployments_Unmarshal(t *testing.T) {
+  var root environmentDeploymentsRoot
+  e := json.Unmarshal([]byte(envDeploymentsJson1), &root)
+  if e != nil {
+    t.Errorf("while unmarshaling, error:\n%#v\n", e)
+    return
+  }
+  got := root.flatten("test", "apis")
+  if len(got) != 2 {
+    t.Errorf("got=%#v", got)
+    return
+  }
+  if got[0].Env != "test" || got[0].Type != "apis" || got[0].Name != "flights" ||
+    got[0].Revision.Number != 4 || got[0].Revision.BasePath() != "/v1/flights" {
+    t.Errorf("first: got=%#v", got[0])
+  }
+  if got[1].Name != "hotels" || got[1].Revision.Number != 2 {
+    t.Errorf("second: got=%#v", got[1])
+  }
+
+  var orgRoot organizationDeploymentsRoot
+  e = json.Unmarshal([]byte(orgDeploymentsJson1), &orgRoot)
+  if e != nil {
+    t.Errorf("while unmarshaling, error:\n%#v\n", e)
+    return
+  }
+  envs := []string{}
+  for _, envRoot := range orgRoot.Environments {
+    for _, d := range envRoot.flatten("", "apis") {
+      envs = append(envs, fmt.Sprintf("%s:%s:%d", d.Env, d.Name, d.Revision.Number))
+    }
+  }
+  if fmt.Sprintf("%v", envs) != "[test:flights:4 prod:flights:3]" {
+    t.Errorf("org: got=%v", envs)
+  }
+}
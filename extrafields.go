@@ -0,0 +1,108 @@
+package apigee
+
+import (
+  "encoding/json"
+  "reflect"
+  "strings"
+)
+
+// ExtraFields holds the JSON fields of an entity that the struct for the
+// entity does not model, keyed by field name. An entity that carries
+// ExtraFields sends them back to Edge when it is marshaled, so that a
+// Get-modify-Update cycle does not erase settings this library does not know about.
+type ExtraFields map[string]json.RawMessage
+
+// knownFields returns the JSON field names of the struct type.
+func knownFields(t reflect.Type) map[string]bool {
+  known := map[string]bool{}
+  for i := 0; i < t.NumField(); i++ {
+    f := t.Field(i)
+    tag := f.Tag.Get("json")
+    if tag == "-" {
+      continue
+    }
+    name := strings.Split(tag, ",")[0]
+    if name == "" {
+      name = f.Name
+    }
+    known[name] = true
+  }
+  return known
+}
+
+// unmarshalWithExtra unmarshals data into v, which must be a pointer to a
+// struct, and collects the fields that v does not model into extra.
+func unmarshalWithExtra(data []byte, v interface{}, extra *ExtraFields) error {
+  e := json.Unmarshal(data, v)
+  if e != nil {
+    return e
+  }
+  all := map[string]json.RawMessage{}
+  e = json.Unmarshal(data, &all)
+  if e != nil {
+    return e
+  }
+  known := knownFields(reflect.TypeOf(v).Elem())
+  *extra = nil
+  for k, raw := range all {
+    if known[k] {
+      continue
+    }
+    if *extra == nil {
+      *extra = ExtraFields{}
+    }
+    (*extra)[k] = raw
+  }
+  return nil
+}
+
+// zeroTimestampFields returns the JSON names of the Timestamp fields of the
+// struct that are tagged omitempty and hold the zero time. encoding/json
+// never omits a struct-typed field, so these are removed after marshaling.
+func zeroTimestampFields(v reflect.Value) []string {
+  names := []string{}
+  t := v.Type()
+  for i := 0; i < t.NumField(); i++ {
+    f := t.Field(i)
+    if f.Type != reflect.TypeOf(Timestamp{}) {
+      continue
+    }
+    parts := strings.Split(f.Tag.Get("json"), ",")
+    if len(parts) < 2 || parts[1] != "omitempty" || parts[0] == "" || parts[0] == "-" {
+      continue
+    }
+    if v.Field(i).Interface().(Timestamp).IsZero() {
+      names = append(names, parts[0])
+    }
+  }
+  return names
+}
+
+// marshalWithExtra marshals v, which must be a struct, adding the extra
+// fields. A field that v models is never overridden by an extra field. Zero
+// Timestamps tagged omitempty are left out.
+func marshalWithExtra(v interface{}, extra ExtraFields) ([]byte, error) {
+  data, e := json.Marshal(v)
+  if e != nil {
+    return data, e
+  }
+  zeroTimestamps := zeroTimestampFields(reflect.ValueOf(v))
+  if len(extra) == 0 && len(zeroTimestamps) == 0 {
+    return data, e
+  }
+  all := map[string]json.RawMessage{}
+  e = json.Unmarshal(data, &all)
+  if e != nil {
+    return nil, e
+  }
+  for _, k := range zeroTimestamps {
+    delete(all, k)
+  }
+  known := knownFields(reflect.TypeOf(v))
+  for k, raw := range extra {
+    if !known[k] {
+      all[k] = raw
+    }
+  }
+  return json.Marshal(all)
+}
@@ -0,0 +1,104 @@
+package apigee
+
+import (
+  "encoding/json"
+  "testing"
+  "time"
+)
+
+// roundTrip unmarshals the canned JSON into v, applies modify, marshals v, and
+// returns the result as a generic map.
+func roundTrip(t *testing.T, canned string, v interface{}, modify func()) map[string]interface{} {
+  e := json.Unmarshal([]byte(canned), v)
+  if e != nil {
+    t.Fatalf("while unmarshaling, error:\n%#v\n", e)
+  }
+  modify()
+  data, e := json.Marshal(v)
+  if e != nil {
+    t.Fatalf("while marshaling, error:\n%#v\n", e)
+  }
+  got := map[string]interface{}{}
+  e = json.Unmarshal(data, &got)
+  if e != nil {
+    t.Fatalf("while unmarshaling result, error:\n%#v\n", e)
+  }
+  return got
+}
+
+func TestExtraFields_RoundTrip(t *testing.T) {
+  var developer Developer
+  var app DeveloperApp
+  var product ApiProduct
+  var env Environment
+  var cache Cache
+  testCases := []struct {
+    entity   string
+    canned   string
+    v        interface{}
+    modify   func()
+    changed  string
+    expected interface{}
+    extra    func() ExtraFields
+  }{
+    {"developer",
+      `{"email":"dev@example.com","firstName":"Dev","status":"active","createdAt":1500000000000,"developerTier":"gold"}`,
+      &developer, func() { developer.FirstName = "Devon" }, "firstName", "Devon",
+      func() ExtraFields { return developer.Extra }},
+    {"developerapp",
+      `{"name":"flights-app","callbackUrl":"https://example.com/cb","appFamily":"default","status":"approved"}`,
+      &app, func() { app.Status = "revoked" }, "status", "revoked",
+      func() ExtraFields { return app.Extra }},
+    {"apiproduct",
+      `{"name":"flights","displayName":"Flights","quotaCounterScope":"PROXY","environments":["test"]}`,
+      &product, func() { product.DisplayName = "Flights!" }, "displayName", "Flights!",
+      func() ExtraFields { return product.Extra }},
+    {"environment",
+      `{"name":"test","createdAt":1500000000000,"lastModifiedAt":1500000000000,"description":"testing","type":"BASE"}`,
+      &env, func() { env.Name = "test2" }, "name", "test2",
+      func() ExtraFields { return env.Extra }},
+    {"cache",
+      `{"name":"c1","description":"","distributed":true,"skipCacheIfElementSizeInKBExceeds":"512","compression":{"minimumSizeInKB":1024}}`,
+      &cache, func() { cache.Description = "session cache" }, "description", "session cache",
+      func() ExtraFields { return cache.Extra }},
+  }
+  for _, tc := range testCases {
+    got := roundTrip(t, tc.canned, tc.v, tc.modify)
+    original := map[string]interface{}{}
+    json.Unmarshal([]byte(tc.canned), &original)
+    if len(tc.extra()) == 0 {
+      t.Errorf("%s: no extra fields captured", tc.entity)
+    }
+    for k := range tc.extra() {
+      if !jsonEqual(original[k], got[k]) {
+        t.Errorf("%s: %s: got=%v, expected=%v", tc.entity, k, got[k], original[k])
+      }
+    }
+    if !jsonEqual(got[tc.changed], tc.expected) {
+      t.Errorf("%s: %s: got=%v, expected=%v", tc.entity, tc.changed, got[tc.changed], tc.expected)
+    }
+  }
+}
+
+func TestExtraFields_NoOverride(t *testing.T) {
+  dev := Developer{Email: "dev@example.com", Extra: ExtraFields{"email": json.RawMessage(`"other@example.com"`)}}
+  data, e := json.Marshal(dev)
+  if e != nil {
+    t.Errorf("while marshaling, error:\n%#v\n", e)
+    return
+  }
+  if string(data) != `{"email":"dev@example.com"}` {
+    t.Errorf("got=%s", data)
+  }
+}
+
+func TestExtraFields_ZeroTimestamps(t *testing.T) {
+  data, e := json.Marshal(Environment{Name: "test", LastModifiedAt: Timestamp{time.Unix(1500000000, 0)}})
+  if e != nil {
+    t.Errorf("while marshaling, error:\n%#v\n", e)
+    return
+  }
+  if string(data) != `{"lastModifiedAt":1500000000000,"name":"test","properties":{}}` {
+    t.Errorf("got=%s", data)
+  }
+}
@@ -0,0 +1,178 @@
+package apigee
+
+import (
+  "archive/zip"
+  "bytes"
+  "crypto/sha256"
+  "encoding/hex"
+  "fmt"
+  "io/ioutil"
+  "os"
+  "path"
+  "path/filepath"
+  "regexp"
+  "sort"
+  "strings"
+)
+
+// fingerprintPrefix marks a fingerprint embedded within the description of a
+// revision, eg "fingerprint:sha256:0a1b2c...".
+const fingerprintPrefix = "fingerprint:sha256:"
+
+var fingerprintMarker = regexp.MustCompile(regexp.QuoteMeta(fingerprintPrefix) + `([0-9a-f]{64})`)
+
+// bundleEntry holds the normalized name and content of one file in a bundle.
+type bundleEntry struct {
+  name    string
+  content []byte
+}
+
+// isBundleDescriptor returns true for the top-level descriptor of a bundle, like
+// apiproxy/foo.xml. Edge rewrites that file on each import, stamping it with
+// the revision number and timestamps, so it is excluded from fingerprints.
+func isBundleDescriptor(name string) bool {
+  parts := strings.Split(name, "/")
+  return len(parts) == 2 && strings.HasSuffix(parts[1], ".xml")
+}
+
+// normalizeBundleContent converts CRLF line endings to LF, so that a bundle
+// checked out on Windows has the same fingerprint as one checked out elsewhere.
+func normalizeBundleContent(content []byte) []byte {
+  return bytes.Replace(content, []byte("\r\n"), []byte("\n"), -1)
+}
+
+func fingerprintEntries(entries []bundleEntry) string {
+  sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+  h := sha256.New()
+  for _, entry := range entries {
+    fmt.Fprintf(h, "%s\n%d\n", entry.name, len(entry.content))
+    h.Write(entry.content)
+  }
+  return hex.EncodeToString(h.Sum(nil))
+}
+
+func readZipBundleEntries(zipfileName string) ([]bundleEntry, error) {
+  r, e := zip.OpenReader(zipfileName)
+  if e != nil {
+    return nil, e
+  }
+  defer r.Close()
+
+  entries := []bundleEntry{}
+  for _, f := range r.File {
+    name := strings.TrimPrefix(path.Clean(strings.Replace(f.Name, "\\", "/", -1)), "/")
+    if f.FileInfo().IsDir() || !smartFilter(name) || isBundleDescriptor(name) {
+      continue
+    }
+    rc, e := f.Open()
+    if e != nil {
+      return nil, e
+    }
+    content, e := ioutil.ReadAll(rc)
+    rc.Close()
+    if e != nil {
+      return nil, e
+    }
+    entries = append(entries, bundleEntry{name, normalizeBundleContent(content)})
+  }
+  return entries, nil
+}
+
+func readDirBundleEntries(bundleDir string) ([]bundleEntry, error) {
+  parent := filepath.Dir(bundleDir)
+  entries := []bundleEntry{}
+  e := filepath.Walk(bundleDir, func(p string, info os.FileInfo, err error) error {
+    if err != nil {
+      return err
+    }
+    if info.IsDir() || !smartFilter(p) {
+      return nil
+    }
+    rel, err := filepath.Rel(parent, p)
+    if err != nil {
+      return err
+    }
+    name := filepath.ToSlash(rel)
+    if isBundleDescriptor(name) {
+      return nil
+    }
+    content, err := ioutil.ReadFile(p)
+    if err != nil {
+      return err
+    }
+    entries = append(entries, bundleEntry{name, normalizeBundleContent(content)})
+    return nil
+  })
+  return entries, e
+}
+
+// BundleFingerprint computes a fingerprint of the normalized content of an API
+// Proxy or SharedFlow bundle. The source can be either a zip file, or a
+// directory containing an exploded apiproxy or sharedflowbundle directory, the
+// same as for Import. The fingerprint does not depend on the order of entries in
+// a zip, on line endings, or on the top-level descriptor file, which Edge
+// rewrites on import.
+func BundleFingerprint(source string) (string, error) {
+  info, e := os.Stat(source)
+  if e != nil {
+    return "", e
+  }
+  var entries []bundleEntry
+  if !info.IsDir() {
+    entries, e = readZipBundleEntries(source)
+  } else {
+    bundleDir := ""
+    for _, candidate := range []string{"apiproxy", "sharedflowbundle"} {
+      if fi, e := os.Stat(filepath.Join(source, candidate)); e == nil && fi.IsDir() {
+        bundleDir = filepath.Join(source, candidate)
+        break
+      }
+    }
+    if bundleDir == "" {
+      return "", fmt.Errorf("%s contains neither an apiproxy nor a sharedflowbundle directory", source)
+    }
+    entries, e = readDirBundleEntries(bundleDir)
+  }
+  if e != nil {
+    return "", e
+  }
+  return fingerprintEntries(entries), nil
+}
+
+// fingerprintFromDescription extracts a fingerprint previously stored in the
+// description of a revision, if any.
+func fingerprintFromDescription(description string) string {
+  m := fingerprintMarker.FindStringSubmatch(description)
+  if m == nil {
+    return ""
+  }
+  return m[1]
+}
+
+// FingerprintDescription returns a marker suitable for inclusion in the
+// Description element of a bundle descriptor. When the latest revision carries
+// such a marker, ImportIfChanged compares against it rather than exporting
+// that revision.
+func FingerprintDescription(fingerprint string) string {
+  return fingerprintPrefix + fingerprint
+}
+
+// fingerprintRevision computes the fingerprint of an existing revision by
+// exporting it into a temporary zip file.
+func fingerprintRevision(client *ApigeeClient, uriPathElement, assetName string, rev Revision) (string, *Response, error) {
+  out, e := ioutil.TempFile("", "go-apigee-export-*.zip")
+  if e != nil {
+    return "", nil, e
+  }
+  defer os.Remove(out.Name())
+
+  resp, e := exportBundle(client, uriPathElement, assetName, rev, out)
+  if cerr := out.Close(); e == nil {
+    e = cerr
+  }
+  if e != nil {
+    return "", resp, e
+  }
+  fingerprint, e := BundleFingerprint(out.Name())
+  return fingerprint, resp, e
+}
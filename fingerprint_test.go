@@ -0,0 +1,106 @@
+package apigee
+
+import (
+  "io/ioutil"
+  "os"
+  "path"
+  "strings"
+  "testing"
+)
+
+func TestBundleFingerprint_DirAndZip(t *testing.T) {
+  source := path.Join(proxyBundleDir, "apiproxy-resourcetest1")
+  fromDir, e := BundleFingerprint(source)
+  if e != nil {
+    t.Errorf("while fingerprinting directory, error:\n%#v\n", e)
+    return
+  }
+
+  tempDir, e := ioutil.TempDir("", "go-apigee-test-")
+  if e != nil {
+    t.Errorf("while creating temp dir, error:\n%#v\n", e)
+    return
+  }
+  defer os.RemoveAll(tempDir)
+  zipfileName := path.Join(tempDir, "bundle.zip")
+  e = zipDirectory(path.Join(source, "apiproxy"), zipfileName, smartFilter)
+  if e != nil {
+    t.Errorf("while zipping, error:\n%#v\n", e)
+    return
+  }
+  fromZip, e := BundleFingerprint(zipfileName)
+  if e != nil {
+    t.Errorf("while fingerprinting zip, error:\n%#v\n", e)
+    return
+  }
+  if fromDir != fromZip {
+    t.Errorf("fingerprint mismatch: dir=%s zip=%s", fromDir, fromZip)
+  }
+
+  other, e := BundleFingerprint(path.Join(proxyBundleDir, "apiproxy-library"))
+  if e != nil {
+    t.Errorf("while fingerprinting directory, error:\n%#v\n", e)
+    return
+  }
+  if other == fromDir {
+    t.Errorf("distinct bundles have the same fingerprint: %s", other)
+  }
+}
+
+func TestBundleFingerprint_Normalization(t *testing.T) {
+  testCases := []struct {
+    desc     string
+    entries1 []bundleEntry
+    entries2 []bundleEntry
+    equal    bool
+  }{
+    {"order",
+      []bundleEntry{{"apiproxy/policies/a.xml", []byte("a")}, {"apiproxy/policies/b.xml", []byte("b")}},
+      []bundleEntry{{"apiproxy/policies/b.xml", []byte("b")}, {"apiproxy/policies/a.xml", []byte("a")}},
+      true},
+    {"content",
+      []bundleEntry{{"apiproxy/policies/a.xml", []byte("a")}},
+      []bundleEntry{{"apiproxy/policies/a.xml", []byte("b")}},
+      false},
+    {"name",
+      []bundleEntry{{"apiproxy/policies/a.xml", []byte("a")}},
+      []bundleEntry{{"apiproxy/policies/c.xml", []byte("a")}},
+      false},
+    {"line endings",
+      []bundleEntry{{"apiproxy/policies/a.xml", normalizeBundleContent([]byte("<a>\r\n</a>\r\n"))}},
+      []bundleEntry{{"apiproxy/policies/a.xml", normalizeBundleContent([]byte("<a>\n</a>\n"))}},
+      true},
+  }
+  for _, tc := range testCases {
+    equal := fingerprintEntries(tc.entries1) == fingerprintEntries(tc.entries2)
+    if equal != tc.equal {
+      t.Errorf("%s: equal[got=%v, expected=%v]", tc.desc, equal, tc.equal)
+    }
+  }
+}
+
+func TestBundleFingerprint_Descriptor(t *testing.T) {
+  testCases := []struct {
+    name     string
+    expected bool
+  }{
+    {"apiproxy/foo.xml", true},
+    {"sharedflowbundle/foo.xml", true},
+    {"apiproxy/policies/foo.xml", false},
+    {"apiproxy/resources/jsc/foo.js", false},
+  }
+  for _, tc := range testCases {
+    if got := isBundleDescriptor(tc.name); got != tc.expected {
+      t.Errorf("%s: got=%v, expected=%v", tc.name, got, tc.expected)
+    }
+  }
+
+  fingerprint := strings.Repeat("0a", 32)
+  description := "Deployed by pipeline. " + FingerprintDescription(fingerprint)
+  if got := fingerprintFromDescription(description); got != fingerprint {
+    t.Errorf("fingerprintFromDescription: got=%s, expected=%s", got, fingerprint)
+  }
+  if got := fingerprintFromDescription("no marker here"); got != "" {
+    t.Errorf("fingerprintFromDescription: got=%s, expected empty", got)
+  }
+}
@@ -0,0 +1,132 @@
+package apigee
+
+import (
+  "path"
+  "errors"
+  "fmt"
+)
+
+const flowHooksPath = "flowhooks"
+
+// The attachment points for shared flows within an environment.
+const (
+  PreProxyFlowHook   = "PreProxyFlowHook"
+  PostProxyFlowHook  = "PostProxyFlowHook"
+  PreTargetFlowHook  = "PreTargetFlowHook"
+  PostTargetFlowHook = "PostTargetFlowHook"
+)
+
+// FlowHooksService is an interface for interfacing with the Apigee Edge Admin API
+// dealing with the flow hooks of an environment, to which shared flows attach.
+type FlowHooksService interface {
+  List(string) ([]string, *Response, error)
+  Get(string, string) (*FlowHook, *Response, error)
+  Attach(FlowHook, string) (*FlowHook, *Response, error)
+  Detach(string, string) (*FlowHook, *Response, error)
+}
+
+type FlowHooksServiceOp struct {
+  client *ApigeeClient
+}
+
+var _ FlowHooksService = &FlowHooksServiceOp{}
+
+// FlowHook holds the shared flow attached to a flow hook in an environment.
+// Name is one of PreProxyFlowHook, PostProxyFlowHook, PreTargetFlowHook, or
+// PostTargetFlowHook.
+type FlowHook struct {
+  Name             string   `json:"-"`
+  SharedFlow       string   `json:"sharedFlow,omitempty"`
+  ContinueOnError  bool     `json:"continueOnError"`
+  Description      string   `json:"description,omitempty"`
+}
+
+func flowHookPath(env string, elements ...string) string {
+  return path.Join(append([]string{"e", env, flowHooksPath}, elements...)...)
+}
+
+func validFlowHook(name string) bool {
+  switch name {
+    case PreProxyFlowHook, PostProxyFlowHook, PreTargetFlowHook, PostTargetFlowHook:
+      return true
+  }
+  return false
+}
+
+// List retrieves the names of the flow hooks in an environment.
+func (s *FlowHooksServiceOp) List(env string) ([]string, *Response, error) {
+  req, e := s.client.NewRequest("GET", flowHookPath(env), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  namelist := make([]string,0)
+  resp, e := s.client.Do(req, &namelist)
+  if e != nil {
+    return nil, resp, e
+  }
+  return namelist, resp, e
+}
+
+// Get retrieves a flow hook in an environment, including the name of the shared
+// flow attached to it, if any.
+func (s *FlowHooksServiceOp) Get(name, env string) (*FlowHook, *Response, error) {
+  req, e := s.client.NewRequest("GET", flowHookPath(env, name), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedHook := FlowHook{}
+  resp, e := s.client.Do(req, &returnedHook)
+  if e != nil {
+    return nil, resp, e
+  }
+  returnedHook.Name = name
+  return &returnedHook, resp, e
+}
+
+// Attach attaches a shared flow to a flow hook in an environment. The shared
+// flow must be deployed in that environment; Attach checks this first.
+func (s *FlowHooksServiceOp) Attach(hook FlowHook, env string) (*FlowHook, *Response, error) {
+  if !validFlowHook(hook.Name) {
+    return nil, nil, fmt.Errorf("unknown flow hook: %s", hook.Name)
+  }
+  if hook.SharedFlow == "" {
+    return nil, nil, errors.New("must specify the SharedFlow to attach")
+  }
+  deployment, resp, e := s.client.SharedFlows.GetDeployments(hook.SharedFlow)
+  if e != nil {
+    return nil, resp, e
+  }
+  if newestDeployed(deployedInEnv(deployment, env)) == nil {
+    return nil, nil, fmt.Errorf("shared flow %s is not deployed in %s", hook.SharedFlow, env)
+  }
+
+  req, e := s.client.NewRequest("PUT", flowHookPath(env, hook.Name), hook)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedHook := FlowHook{}
+  resp, e = s.client.Do(req, &returnedHook)
+  if e != nil {
+    return nil, resp, e
+  }
+  returnedHook.Name = hook.Name
+  return &returnedHook, resp, e
+}
+
+// Detach removes the shared flow attached to a flow hook in an environment.
+func (s *FlowHooksServiceOp) Detach(name, env string) (*FlowHook, *Response, error) {
+  if !validFlowHook(name) {
+    return nil, nil, fmt.Errorf("unknown flow hook: %s", name)
+  }
+  req, e := s.client.NewRequest("DELETE", flowHookPath(env, name), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  detachedHook := FlowHook{}
+  resp, e := s.client.Do(req, &detachedHook)
+  if e != nil {
+    return nil, resp, e
+  }
+  detachedHook.Name = name
+  return &detachedHook, resp, e
+}
@@ -0,0 +1,43 @@
+package apigee
+
+import (
+  "encoding/json"
+  "testing"
+)
+
+const (
+  flowHookJson1 = `{
+  "continueOnError" : true,
+  "sharedFlow" : "sf-security",
+  "description" : "applies to every proxy"
+}`
+)
+
+func TestFlowHook_Unmarshal(t *testing.T) {
+  var got FlowHook
+  e := json.Unmarshal([]byte(flowHookJson1), &got)
+  if e != nil {
+    t.Errorf("while unmarshaling, error:\n%#v\n", e)
+    return
+  }
+  if got.SharedFlow != "sf-security" || !got.ContinueOnError || got.Description != "applies to every proxy" {
+    t.Errorf("got=%#v", got)
+  }
+
+  out, _ := json.Marshal(FlowHook{Name: PreProxyFlowHook, SharedFlow: "sf-security"})
+  expected := `{"sharedFlow":"sf-security","continueOnError":false}`
+  if string(out) != expected {
+    t.Errorf("marshal: got=%s, expected=%s", out, expected)
+  }
+}
+
+func TestFlowHook_Names(t *testing.T) {
+  for _, name := range []string{PreProxyFlowHook, PostProxyFlowHook, PreTargetFlowHook, PostTargetFlowHook} {
+    if !validFlowHook(name) {
+      t.Errorf("%s: expected valid", name)
+    }
+  }
+  if validFlowHook("PreflowHook") {
+    t.Errorf("PreflowHook: expected invalid")
+  }
+}
@@ -0,0 +1,201 @@
+package apigee
+
+import (
+  "errors"
+  "fmt"
+  "time"
+)
+
+// KeyRotation describes the outcome of rotating a consumer key of an app.
+type KeyRotation struct {
+  App        string
+  OldKey     string
+  NewKey     *Credential
+  // the time at which the old key stops working, as reported by Edge; zero if
+  // the old key has no expiry
+  OldKeyExpiresAt time.Time
+}
+
+// RetiredKey describes a consumer key that was revoked or deleted because its
+// grace period had passed.
+type RetiredKey struct {
+  App          string
+  ConsumerKey  string
+  ExpiresAt    Timestamp
+  // "revoked" or "deleted"
+  Action       string
+}
+
+// ExpiringKey identifies a consumer key, and the app and the developer or
+// company that own it, that expires within a window.
+type ExpiringKey struct {
+  // "developer" or "company"
+  OwnerType    string
+  // the email of the developer, or the name of the company
+  Owner        string
+  App          string
+  ConsumerKey  string
+  Status       string
+  ExpiresAt    Timestamp
+}
+
+// The payload for setting the expiry of an existing key. Edge requires the
+// API Products of the key in the same request.
+type keyExpiryRequest struct {
+  ApiProducts   []string   `json:"apiProducts"`
+  KeyExpiresIn  *Timespan  `json:"keyExpiresIn"`
+}
+
+// This is just a wrapper struct to aid in de-serialization of expanded app lists.
+type developerAppsRoot struct {
+  Apps []DeveloperApp `json:"app"`
+}
+
+// approvedProducts returns the names of the API Products approved on a key.
+// Products that are revoked or pending on the key are left out.
+func approvedProducts(c Credential) []string {
+  names := []string{}
+  for _, p := range c.ApiProducts {
+    if p.Status == "approved" {
+      names = append(names, p.Name)
+    }
+  }
+  return names
+}
+
+// RotateKey issues a new consumer key for an app, with the API Products that
+// are approved on the existing key; products revoked or pending on the existing
+// key are not carried over. The new key never expires. If grace is not nil, the old key
+// is set to expire after the grace period, so that clients can move to the new
+// key without downtime; use RetireExpiredKeys afterwards to revoke or delete
+// it. If grace is nil, the old key is left unchanged. If Edge accepts the grace
+// period but reports no expiry for the old key, RotateKey returns the rotation,
+// with a zero OldKeyExpiresAt, and an error.
+func (s *DeveloperAppsServiceOp) RotateKey(appName, oldKey string, grace *Timespan) (*KeyRotation, error) {
+  app, _, e := s.Get(appName)
+  if e != nil {
+    return nil, e
+  }
+  old := findCredential(app.Credentials, oldKey)
+  if old == nil {
+    return nil, fmt.Errorf("app %s has no key %s", appName, oldKey)
+  }
+  products := approvedProducts(*old)
+  if len(products) == 0 {
+    return nil, fmt.Errorf("key %s of app %s has no approved API Products to carry over", oldKey, appName)
+  }
+  created, _, e := s.CreateKey(appName, products, nil)
+  if e != nil {
+    return nil, e
+  }
+  rotation := &KeyRotation{App: appName, OldKey: oldKey, NewKey: created}
+  if grace == nil {
+    return rotation, nil
+  }
+  req, e := s.client.NewRequest("POST", s.keyPath(appName, oldKey), keyExpiryRequest{products, grace})
+  if e != nil {
+    return rotation, e
+  }
+  req = markSensitive(req)
+  updated := Credential{}
+  _, e = s.client.Do(req, &updated)
+  if e != nil {
+    return rotation, e
+  }
+  if updated.NeverExpires() {
+    return rotation, fmt.Errorf("Edge shows no expiry for key %s of app %s after setting a grace period of %v", oldKey, appName, grace.Duration)
+  }
+  rotation.OldKeyExpiresAt = updated.ExpiresAt.Time
+  return rotation, nil
+}
+
+// keysPastExpiry returns the credentials that expired before now.
+func keysPastExpiry(credentials []Credential, now time.Time) []Credential {
+  expired := []Credential{}
+  for _, c := range credentials {
+    if c.ExpiresBefore(now) {
+      expired = append(expired, c)
+    }
+  }
+  return expired
+}
+
+// RetireExpiredKeys finds the keys of an app whose expiry has passed, and
+// deletes them if remove is true, or else revokes those not already revoked.
+// It returns the keys it retired, up to any error.
+func (s *DeveloperAppsServiceOp) RetireExpiredKeys(appName string, remove bool) ([]RetiredKey, error) {
+  app, _, e := s.Get(appName)
+  if e != nil {
+    return nil, e
+  }
+  retired := []RetiredKey{}
+  for _, c := range keysPastExpiry(app.Credentials, time.Now()) {
+    key := RetiredKey{App: appName, ConsumerKey: c.ConsumerKey, ExpiresAt: c.ExpiresAt}
+    if remove {
+      _, _, e = s.DeleteKey(appName, c.ConsumerKey)
+      key.Action = "deleted"
+    } else if c.Status != "revoked" {
+      _, e = s.RevokeKey(appName, c.ConsumerKey)
+      key.Action = "revoked"
+    } else {
+      continue
+    }
+    if e != nil {
+      return retired, e
+    }
+    retired = append(retired, key)
+  }
+  return retired, nil
+}
+
+// expiringKeys returns the keys of the apps that expire before the deadline,
+// including keys that have already expired. The Owner of a key on a developer
+// app is the developer id, for the caller to resolve.
+func expiringKeys(apps []DeveloperApp, deadline time.Time) []ExpiringKey {
+  expiring := []ExpiringKey{}
+  for _, app := range apps {
+    ownerType, owner := "developer", app.DeveloperId
+    if app.CompanyName != "" {
+      ownerType, owner = "company", app.CompanyName
+    }
+    for _, c := range app.Credentials {
+      if c.ExpiresBefore(deadline) {
+        expiring = append(expiring, ExpiringKey{OwnerType: ownerType, Owner: owner, App: app.Name, ConsumerKey: c.ConsumerKey, Status: c.Status, ExpiresAt: c.ExpiresAt})
+      }
+    }
+  }
+  return expiring
+}
+
+// ExpiringKeys reports the consumer keys of all the apps in the organization,
+// of developers and of companies, that expire within the window from now,
+// including keys that have already expired. Keys without an expiry are not
+// reported.
+func (s *DevelopersServiceOp) ExpiringKeys(within *Timespan) ([]ExpiringKey, error) {
+  if within == nil {
+    return nil, errors.New("must specify the window for expiring keys")
+  }
+  deadline := time.Now().Add(within.Duration)
+  apps, e := (&AppsServiceOp{client: s.client}).listAll()
+  if e != nil {
+    return nil, e
+  }
+  expiring := expiringKeys(apps, deadline)
+  // the apps name their developer by id; report the email instead
+  emails := map[string]string{}
+  for i := range expiring {
+    if expiring[i].OwnerType != "developer" {
+      continue
+    }
+    id := expiring[i].Owner
+    if _, ok := emails[id]; !ok {
+      developer, _, e := s.Get(id)
+      if e != nil {
+        return expiring, e
+      }
+      emails[id] = developer.Email
+    }
+    expiring[i].Owner = emails[id]
+  }
+  return expiring, nil
+}
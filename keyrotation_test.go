@@ -0,0 +1,209 @@
+package apigee
+
+import (
+  "encoding/json"
+  "fmt"
+  "net/http"
+  "strings"
+  "testing"
+  "time"
+)
+
+const (
+  expandedAppsJson1 = `{
+  "app" : [ {
+    "appId" : "a1",
+    "name" : "flights-app",
+    "developerId" : "d1",
+    "credentials" : [
+      { "consumerKey" : "key1", "expiresAt" : -1, "status" : "approved" },
+      { "consumerKey" : "key2", "expiresAt" : 1924992000000, "status" : "approved" }
+    ]
+  }, {
+    "appId" : "a2",
+    "name" : "hotels-app",
+    "companyName" : "acme",
+    "credentials" : [
+      { "consumerKey" : "key3", "expiresAt" : 1577836800000, "status" : "revoked" },
+      { "consumerKey" : "key4", "expiresAt" : 1956528000000, "status" : "approved" }
+    ]
+  } ]
+}`
+)
+
+func TestExpiringKeys(t *testing.T) {
+  var root developerAppsRoot
+  e := json.Unmarshal([]byte(expandedAppsJson1), &root)
+  if e != nil {
+    t.Errorf("while unmarshaling, error:\n%#v\n", e)
+    return
+  }
+  // 2031-02-01 is after key2 (2031-01-01) and before key4 (2032-01-01)
+  deadline := time.Date(2031, 2, 1, 0, 0, 0, 0, time.UTC)
+  got := expiringKeys(root.Apps, deadline)
+  expected := []ExpiringKey{
+    {"developer", "d1", "flights-app", "key2", "approved", Timestamp{}},
+    {"company", "acme", "hotels-app", "key3", "revoked", Timestamp{}},
+  }
+  if len(got) != len(expected) {
+    t.Errorf("got=%#v, expected=%#v", got, expected)
+    return
+  }
+  for i := range expected {
+    got[i].ExpiresAt = Timestamp{}
+    if got[i] != expected[i] {
+      t.Errorf("%d: got=%#v, expected=%#v", i, got[i], expected[i])
+    }
+  }
+}
+
+func TestDevelopersExpiringKeys(t *testing.T) {
+  calls := []string{}
+  client, stop := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+    calls = append(calls, r.URL.Path)
+    switch r.URL.Path {
+    case "/v1/o/org1/apps":
+      fmt.Fprint(w, expandedAppsJson1)
+    case "/v1/o/org1/developers/d1":
+      fmt.Fprint(w, `{"email":"dev@example.com","developerId":"d1"}`)
+    default:
+      http.Error(w, `{"code":"unexpected"}`, http.StatusBadRequest)
+    }
+  })
+  defer stop()
+  // the window reaches past 2032, so every key with an expiry is reported
+  got, e := client.Developers.ExpiringKeys(NewTimespan("5000d"))
+  if e != nil {
+    t.Errorf("unexpected error: %v", e)
+    return
+  }
+  owners := []string{}
+  for _, k := range got {
+    owners = append(owners, k.OwnerType+" "+k.Owner+" "+k.ConsumerKey)
+  }
+  expected := []string{"developer dev@example.com key2", "company acme key3", "company acme key4"}
+  if strings.Join(owners, ",") != strings.Join(expected, ",") {
+    t.Errorf("got=%q, expected=%q", owners, expected)
+  }
+  if len(calls) != 2 {
+    t.Errorf("calls: got=%q", calls)
+  }
+}
+
+func TestKeysPastExpiry(t *testing.T) {
+  var root developerAppsRoot
+  e := json.Unmarshal([]byte(expandedAppsJson1), &root)
+  if e != nil {
+    t.Errorf("while unmarshaling, error:\n%#v\n", e)
+    return
+  }
+  now := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+  got := keysPastExpiry(root.Apps[1].Credentials, now)
+  if len(got) != 1 || got[0].ConsumerKey != "key3" {
+    t.Errorf("got=%#v", got)
+  }
+  if got := keysPastExpiry(root.Apps[0].Credentials, now); len(got) != 0 {
+    t.Errorf("got=%#v", got)
+  }
+}
+
+func TestKeyExpiryRequest_Marshal(t *testing.T) {
+  body, e := json.Marshal(keyExpiryRequest{[]string{"flights-basic"}, NewTimespan("30d")})
+  if e != nil {
+    t.Errorf("while marshaling, error:\n%#v\n", e)
+    return
+  }
+  expected := `{"apiProducts":["flights-basic"],"keyExpiresIn":2592000000}`
+  if string(body) != expected {
+    t.Errorf("got=%s, expected=%s", body, expected)
+  }
+}
+
+func TestRotateKey_Expiry(t *testing.T) {
+  testCases := []struct {
+    desc       string
+    expiresAt  int64
+    wantErr    bool
+  }{
+    {"expiry applied", 1924992000000, false},
+    {"no expiry reported", -1, true},
+  }
+  for _, tc := range testCases {
+    client, stop := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+      p := strings.TrimPrefix(r.URL.Path, "/v1/o/org1/developers/dev@example.com/apps/flights-app")
+      switch {
+      case r.Method == "GET" && p == "":
+        fmt.Fprint(w, `{"name":"flights-app","credentials":[{"consumerKey":"key1","expiresAt":-1,"apiProducts":[{"apiproduct":"p1","status":"approved"}]}]}`)
+      case r.Method == "POST" && p == "":
+        fmt.Fprint(w, `{"name":"flights-app","credentials":[{"consumerKey":"key1","expiresAt":-1},{"consumerKey":"key2","expiresAt":-1}]}`)
+      case r.Method == "POST" && p == "/keys/key1":
+        fmt.Fprintf(w, `{"consumerKey":"key1","expiresAt":%d}`, tc.expiresAt)
+      default:
+        http.Error(w, `{"code":"unexpected"}`, http.StatusBadRequest)
+      }
+    })
+    rotation, e := client.Developers.Apps("dev@example.com").RotateKey("flights-app", "key1", NewTimespan("7d"))
+    stop()
+    if (e != nil) != tc.wantErr {
+      t.Errorf("%s: err[got=%v, expected=%v]", tc.desc, e, tc.wantErr)
+      continue
+    }
+    if rotation == nil || rotation.NewKey == nil || rotation.NewKey.ConsumerKey != "key2" {
+      t.Errorf("%s: got=%#v", tc.desc, rotation)
+      continue
+    }
+    if tc.wantErr {
+      if !rotation.OldKeyExpiresAt.IsZero() {
+        t.Errorf("%s: OldKeyExpiresAt got=%v, expected zero", tc.desc, rotation.OldKeyExpiresAt)
+      }
+      continue
+    }
+    expected := time.Unix(0, tc.expiresAt*int64(time.Millisecond))
+    if !rotation.OldKeyExpiresAt.Equal(expected) {
+      t.Errorf("%s: OldKeyExpiresAt got=%v, expected=%v", tc.desc, rotation.OldKeyExpiresAt, expected)
+    }
+  }
+}
+
+func TestRotateKey_RevokedProduct(t *testing.T) {
+  bodies := map[string]keyExpiryRequest{}
+  client, stop := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+    p := strings.TrimPrefix(r.URL.Path, "/v1/o/org1/developers/dev@example.com/apps/flights-app")
+    if r.Method == "POST" {
+      body := keyExpiryRequest{}
+      json.NewDecoder(r.Body).Decode(&body)
+      bodies[p] = body
+    }
+    switch {
+    case r.Method == "GET" && p == "":
+      fmt.Fprint(w, `{"name":"flights-app","credentials":[{"consumerKey":"key1","expiresAt":-1,"apiProducts":[
+        {"apiproduct":"p1","status":"approved"},
+        {"apiproduct":"p2","status":"revoked"},
+        {"apiproduct":"p3","status":"pending"}]}]}`)
+    case r.Method == "POST" && p == "":
+      fmt.Fprint(w, `{"name":"flights-app","credentials":[{"consumerKey":"key1","expiresAt":-1},{"consumerKey":"key2","expiresAt":-1}]}`)
+    case r.Method == "POST" && p == "/keys/key1":
+      fmt.Fprint(w, `{"consumerKey":"key1","expiresAt":1924992000000}`)
+    default:
+      http.Error(w, `{"code":"unexpected"}`, http.StatusBadRequest)
+    }
+  })
+  defer stop()
+  _, e := client.Developers.Apps("dev@example.com").RotateKey("flights-app", "key1", NewTimespan("7d"))
+  if e != nil {
+    t.Errorf("unexpected error: %v", e)
+    return
+  }
+  for _, p := range []string{"", "/keys/key1"} {
+    got := bodies[p].ApiProducts
+    if len(got) != 1 || got[0] != "p1" {
+      t.Errorf("POST %q: apiProducts got=%v, expected=[p1]", p, got)
+    }
+  }
+
+  // a key with no approved products has nothing to carry over
+  none := Credential{ApiProducts: []CredentialProduct{{"p2", "revoked"}}}
+  if got := approvedProducts(none); len(got) != 0 {
+    t.Errorf("approvedProducts: got=%v", got)
+  }
+}
@@ -0,0 +1,440 @@
+package apigee
+
+import (
+  "bytes"
+  "errors"
+  "fmt"
+  "io"
+  "mime/multipart"
+  "net/url"
+  "os"
+  "path"
+  "path/filepath"
+  "strings"
+  "time"
+)
+
+const keystoresPath = "keystores"
+
+// Formats for uploading a key and certificate to create an alias in a keystore.
+const (
+  // a PEM key file and a PEM certificate file
+  AliasFormatPEM    = "keycertfile"
+  // a JAR file holding the key and certificate
+  AliasFormatJAR    = "keycertjar"
+  // a PKCS12 (.p12, .pfx) file holding the key and certificate
+  AliasFormatPKCS12 = "pkcs12"
+  aliasFormatSelfSigned = "selfsignedcert"
+)
+
+// KeystoresService is an interface for interfacing with the Apigee Edge Admin API
+// dealing with keystores and truststores in an environment. Edge manages both
+// through the same API; a truststore is a keystore holding only certificates.
+type KeystoresService interface {
+  List(string) ([]string, *Response, error)
+  Get(string, string) (*Keystore, *Response, error)
+  Create(string, string) (*Keystore, *Response, error)
+  Delete(string, string) (*Keystore, *Response, error)
+  ListAliases(string, string) ([]string, *Response, error)
+  GetAlias(string, string, string) (*KeyAlias, *Response, error)
+  CreateAlias(string, AliasUpload, string) (*KeyAlias, *Response, error)
+  CreateSelfSignedAlias(string, SelfSignedCert, string) (*KeyAlias, *Response, error)
+  DeleteAlias(string, string, string) (*KeyAlias, *Response, error)
+  GenerateCSR(string, string, string) (string, *Response, error)
+  ExpiringCertificates(int) ([]ExpiringCert, error)
+}
+
+type KeystoresServiceOp struct {
+  client *ApigeeClient
+}
+
+var _ KeystoresService = &KeystoresServiceOp{}
+
+// Keystore contains information about a keystore or truststore within an Edge environment.
+type Keystore struct {
+  Name     string            `json:"name,omitempty"`
+  Certs    []string          `json:"certs,omitempty"`
+  Keys     []string          `json:"keys,omitempty"`
+  Aliases  []KeystoreEntry   `json:"aliases,omitempty"`
+}
+
+// KeystoreEntry names the key and certificates held under one alias of a keystore.
+type KeystoreEntry struct {
+  AliasName  string     `json:"aliasName,omitempty"`
+  Certs      []string   `json:"cert,omitempty"`
+  Key        string     `json:"key,omitempty"`
+}
+
+// KeyAlias holds the details of one alias within a keystore, including the
+// details of its certificates.
+type KeyAlias struct {
+  Alias      string     `json:"alias,omitempty"`
+  KeyName    string     `json:"keyName,omitempty"`
+  Type       string     `json:"type,omitempty"`
+  CertsInfo  CertsInfo  `json:"certsInfo,omitempty"`
+}
+
+// CertsInfo holds the details of the certificate chain of an alias.
+type CertsInfo struct {
+  CertName   string       `json:"certName,omitempty"`
+  CertInfo   []CertInfo   `json:"certInfo,omitempty"`
+}
+
+// CertInfo holds the details of one certificate, as reported by Edge.
+type CertInfo struct {
+  Subject                  string     `json:"subject,omitempty"`
+  Issuer                   string     `json:"issuer,omitempty"`
+  SubjectAlternativeNames  []string   `json:"subjectAlternativeNames,omitempty"`
+  SerialNumber             string     `json:"serialNumber,omitempty"`
+  SigAlgName               string     `json:"sigAlgName,omitempty"`
+  Version                  int        `json:"version,omitempty"`
+  BasicConstraints         string     `json:"basicConstraints,omitempty"`
+  PublicKey                string     `json:"publicKey,omitempty"`
+  IsValid                  string     `json:"isValid,omitempty"`
+  ValidFrom                Timestamp  `json:"validFrom,omitempty"`
+  ExpiryDate               Timestamp  `json:"expiryDate,omitempty"`
+}
+
+// CommonName returns the CN from the subject of the certificate, or the empty
+// string if there is none.
+func (c CertInfo) CommonName() string {
+  for _, part := range strings.Split(c.Subject, ",") {
+    part = strings.TrimSpace(part)
+    if strings.HasPrefix(part, "CN=") {
+      return strings.TrimPrefix(part, "CN=")
+    }
+  }
+  return ""
+}
+
+// ExpiresWithin returns true if the certificate expires before now plus the
+// given duration, including certificates that have already expired.
+func (c CertInfo) ExpiresWithin(d time.Duration) bool {
+  return c.ExpiryDate.Time.Before(time.Now().Add(d))
+}
+
+// AliasUpload describes the key and certificate files to upload to create an
+// alias. For Format AliasFormatPEM, specify CertFile, and KeyFile unless
+// uploading only a certificate, as for a truststore. For AliasFormatJAR and
+// AliasFormatPKCS12, specify File. Password is needed for an encrypted key or
+// a PKCS12 file.
+type AliasUpload struct {
+  Alias          string
+  Format         string
+  KeyFile        string
+  CertFile       string
+  File           string
+  Password       string
+  // Optional. Accept a certificate that has expired.
+  IgnoreExpiry   bool
+}
+
+// SelfSignedCert describes a self-signed certificate for Edge to generate.
+type SelfSignedCert struct {
+  Alias                  string            `json:"alias"`
+  KeySize                string            `json:"keySize,omitempty"`
+  SigAlg                 string            `json:"sigAlg,omitempty"`
+  Subject                CertSubject       `json:"subject"`
+  CertValidityInDays     string            `json:"certValidityInDays,omitempty"`
+  SubjectAlternativeDNSNames *SubjectAlternativeNames `json:"subjectAlternativeDNSNames,omitempty"`
+}
+
+// CertSubject holds the subject of a certificate to generate.
+type CertSubject struct {
+  CommonName        string   `json:"commonName,omitempty"`
+  Email             string   `json:"email,omitempty"`
+  CountryCode       string   `json:"countryCode,omitempty"`
+  State             string   `json:"state,omitempty"`
+  Locality          string   `json:"locality,omitempty"`
+  Org               string   `json:"org,omitempty"`
+  OrgUnit           string   `json:"orgUnit,omitempty"`
+}
+
+// SubjectAlternativeNames holds the DNS names to include in a certificate to generate.
+type SubjectAlternativeNames struct {
+  SubjectAlternativeName []string `json:"subjectAlternativeName,omitempty"`
+}
+
+func keystorePath(env string, elements ...string) string {
+  return path.Join(append([]string{"e", env, keystoresPath}, elements...)...)
+}
+
+// List retrieves the names of the keystores and truststores in an environment.
+func (s *KeystoresServiceOp) List(env string) ([]string, *Response, error) {
+  req, e := s.client.NewRequest("GET", keystorePath(env), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  namelist := make([]string,0)
+  resp, e := s.client.Do(req, &namelist)
+  if e != nil {
+    return nil, resp, e
+  }
+  return namelist, resp, e
+}
+
+// Get retrieves a keystore, including the names of its aliases, keys, and certificates.
+func (s *KeystoresServiceOp) Get(name, env string) (*Keystore, *Response, error) {
+  req, e := s.client.NewRequest("GET", keystorePath(env, name), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedKeystore := Keystore{}
+  resp, e := s.client.Do(req, &returnedKeystore)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedKeystore, resp, e
+}
+
+// Create creates an empty keystore or truststore in an environment.
+func (s *KeystoresServiceOp) Create(name, env string) (*Keystore, *Response, error) {
+  if name == "" {
+    return nil, nil, errors.New("cannot create a keystore with no name")
+  }
+  req, e := s.client.NewRequest("POST", keystorePath(env), Keystore{Name: name})
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedKeystore := Keystore{}
+  resp, e := s.client.Do(req, &returnedKeystore)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedKeystore, resp, e
+}
+
+// Delete removes a keystore or truststore from an environment.
+func (s *KeystoresServiceOp) Delete(name, env string) (*Keystore, *Response, error) {
+  req, e := s.client.NewRequest("DELETE", keystorePath(env, name), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  deletedKeystore := Keystore{}
+  resp, e := s.client.Do(req, &deletedKeystore)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &deletedKeystore, resp, e
+}
+
+// ListAliases retrieves the names of the aliases in a keystore.
+func (s *KeystoresServiceOp) ListAliases(keystore, env string) ([]string, *Response, error) {
+  req, e := s.client.NewRequest("GET", keystorePath(env, keystore, "aliases"), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  namelist := make([]string,0)
+  resp, e := s.client.Do(req, &namelist)
+  if e != nil {
+    return nil, resp, e
+  }
+  return namelist, resp, e
+}
+
+// GetAlias retrieves an alias in a keystore, including the details of its
+// certificates: subject, issuer, subject alternative names, and validity dates.
+func (s *KeystoresServiceOp) GetAlias(keystore, alias, env string) (*KeyAlias, *Response, error) {
+  req, e := s.client.NewRequest("GET", keystorePath(env, keystore, "aliases", alias), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedAlias := KeyAlias{}
+  resp, e := s.client.Do(req, &returnedAlias)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedAlias, resp, e
+}
+
+// DeleteAlias removes an alias, with its key and certificates, from a keystore.
+func (s *KeystoresServiceOp) DeleteAlias(keystore, alias, env string) (*KeyAlias, *Response, error) {
+  req, e := s.client.NewRequest("DELETE", keystorePath(env, keystore, "aliases", alias), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  deletedAlias := KeyAlias{}
+  resp, e := s.client.Do(req, &deletedAlias)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &deletedAlias, resp, e
+}
+
+func addFilePart(writer *multipart.Writer, fieldName, filename string) error {
+  f, e := os.Open(filename)
+  if e != nil {
+    return e
+  }
+  defer f.Close()
+  part, e := writer.CreateFormFile(fieldName, filepath.Base(filename))
+  if e != nil {
+    return e
+  }
+  _, e = io.Copy(part, f)
+  return e
+}
+
+// aliasUploadBody builds the multipart form for an alias upload, returning the
+// body and its content type.
+func aliasUploadBody(upload AliasUpload) (*bytes.Buffer, string, error) {
+  body := new(bytes.Buffer)
+  writer := multipart.NewWriter(body)
+  var e error
+  switch upload.Format {
+    case AliasFormatPEM:
+      if upload.CertFile == "" {
+        return nil, "", errors.New("must specify the CertFile for format keycertfile")
+      }
+      if upload.KeyFile != "" {
+        e = addFilePart(writer, "keyFile", upload.KeyFile)
+      }
+      if e == nil {
+        e = addFilePart(writer, "certFile", upload.CertFile)
+      }
+    case AliasFormatJAR, AliasFormatPKCS12:
+      if upload.File == "" {
+        return nil, "", fmt.Errorf("must specify the File for format %s", upload.Format)
+      }
+      e = addFilePart(writer, "file", upload.File)
+    default:
+      return nil, "", fmt.Errorf("unsupported alias format: %s", upload.Format)
+  }
+  if e == nil && upload.Password != "" {
+    e = writer.WriteField("password", upload.Password)
+  }
+  if e == nil {
+    e = writer.Close()
+  }
+  if e != nil {
+    return nil, "", e
+  }
+  return body, writer.FormDataContentType(), nil
+}
+
+// CreateAlias uploads a key and certificate, or a certificate alone, to create
+// an alias in a keystore or truststore.
+func (s *KeystoresServiceOp) CreateAlias(keystore string, upload AliasUpload, env string) (*KeyAlias, *Response, error) {
+  if upload.Alias == "" {
+    return nil, nil, errors.New("must specify the Alias to create")
+  }
+  body, contentType, e := aliasUploadBody(upload)
+  if e != nil {
+    return nil, nil, e
+  }
+
+  // append the query params
+  origURL, e := url.Parse(keystorePath(env, keystore, "aliases"))
+  if e != nil {
+    return nil, nil, e
+  }
+  q := origURL.Query()
+  q.Add("alias", upload.Alias)
+  q.Add("format", upload.Format)
+  if upload.IgnoreExpiry {
+    q.Add("ignoreExpiryValidation", "true")
+  }
+  origURL.RawQuery = q.Encode()
+
+  req, e := s.client.NewRequest("POST", origURL.String(), body)
+  if e != nil {
+    return nil, nil, e
+  }
+  req.Header.Set("Content-Type", contentType)
+  // the upload may hold a private key, encrypted or not
+  req = markSensitive(req)
+  returnedAlias := KeyAlias{}
+  resp, e := s.client.Do(req, &returnedAlias)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedAlias, resp, e
+}
+
+// CreateSelfSignedAlias has Edge generate a key and a self-signed certificate
+// under a new alias in a keystore.
+func (s *KeystoresServiceOp) CreateSelfSignedAlias(keystore string, cert SelfSignedCert, env string) (*KeyAlias, *Response, error) {
+  if cert.Alias == "" {
+    return nil, nil, errors.New("must specify the Alias to create")
+  }
+  // append the query params
+  origURL, e := url.Parse(keystorePath(env, keystore, "aliases"))
+  if e != nil {
+    return nil, nil, e
+  }
+  q := origURL.Query()
+  q.Add("alias", cert.Alias)
+  q.Add("format", aliasFormatSelfSigned)
+  origURL.RawQuery = q.Encode()
+
+  req, e := s.client.NewRequest("POST", origURL.String(), cert)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedAlias := KeyAlias{}
+  resp, e := s.client.Do(req, &returnedAlias)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedAlias, resp, e
+}
+
+// GenerateCSR generates a certificate signing request for the key under an
+// alias, returning the CSR in PEM form.
+func (s *KeystoresServiceOp) GenerateCSR(keystore, alias, env string) (string, *Response, error) {
+  req, e := s.client.NewRequest("POST", keystorePath(env, keystore, "aliases", alias, "csr"), nil)
+  if e != nil {
+    return "", nil, e
+  }
+  req.Header.Set("Accept", "*/*")
+  buf := new(bytes.Buffer)
+  resp, e := s.client.Do(req, buf)
+  if e != nil {
+    return "", resp, e
+  }
+  return buf.String(), resp, e
+}
+
+// ExpiringCert identifies a certificate that expires soon, and where it is held.
+type ExpiringCert struct {
+  Env        string
+  Keystore   string
+  Alias      string
+  Cert       CertInfo
+}
+
+// ExpiringCertificates examines every alias of every keystore and truststore in
+// every environment of the organization, and returns the certificates that
+// expire within the given number of days, including those already expired.
+func (s *KeystoresServiceOp) ExpiringCertificates(days int) ([]ExpiringCert, error) {
+  envs, _, e := s.client.Environments.List()
+  if e != nil {
+    return nil, e
+  }
+  within := time.Duration(days) * 24 * time.Hour
+  expiring := []ExpiringCert{}
+  for _, env := range envs {
+    keystores, _, e := s.List(env)
+    if e != nil {
+      return expiring, e
+    }
+    for _, keystore := range keystores {
+      aliases, _, e := s.ListAliases(keystore, env)
+      if e != nil {
+        return expiring, e
+      }
+      for _, alias := range aliases {
+        keyAlias, _, e := s.GetAlias(keystore, alias, env)
+        if e != nil {
+          return expiring, e
+        }
+        for _, cert := range keyAlias.CertsInfo.CertInfo {
+          if cert.ExpiresWithin(within) {
+            expiring = append(expiring, ExpiringCert{Env: env, Keystore: keystore, Alias: alias, Cert: cert})
+          }
+        }
+      }
+    }
+  }
+  return expiring, nil
+}
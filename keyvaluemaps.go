@@ -0,0 +1,326 @@
+package apigee
+
+import (
+  "path"
+  "errors"
+  "encoding/json"
+  "io/ioutil"
+  "net/http"
+  "sort"
+)
+
+const keyValueMapsPath = "keyvaluemaps"
+
+// KeyValueMapsService is an interface for interfacing with the Apigee Edge Admin API
+// dealing with Key Value Maps. The service attached to the ApigeeClient manages
+// the maps scoped to the organization; use Environment or Proxy to get a service
+// that manages the maps scoped to an environment or to an API Proxy.
+//
+// In debug mode, the bodies of requests and responses for Key Value Maps are
+// not dumped, because they may carry the values of encrypted maps.
+type KeyValueMapsService interface {
+  Environment(string) KeyValueMapsService
+  Proxy(string) KeyValueMapsService
+  List() ([]string, *Response, error)
+  Get(string) (*KeyValueMap, *Response, error)
+  Create(KeyValueMap) (*KeyValueMap, *Response, error)
+  Update(KeyValueMap) (*KeyValueMap, *Response, error)
+  Delete(string) (*KeyValueMap, *Response, error)
+  ListKeys(string, *KeyListOptions) ([]string, *Response, error)
+  GetEntry(string, string) (*KeyValueEntry, *Response, error)
+  CreateEntry(string, KeyValueEntry) (*KeyValueEntry, *Response, error)
+  UpdateEntry(string, KeyValueEntry) (*KeyValueEntry, *Response, error)
+  DeleteEntry(string, string) (*KeyValueEntry, *Response, error)
+  LoadFromFile(string, string, bool) (*KeyValueMap, error)
+}
+
+type KeyValueMapsServiceOp struct {
+  client *ApigeeClient
+  // the path prefix for the scope; empty for the organization
+  scope string
+}
+
+var _ KeyValueMapsService = &KeyValueMapsServiceOp{}
+
+// KeyValueMap contains information about a Key Value Map. The Entries marshal to
+// and from the name/value array form that Edge uses. For an encrypted map, Edge
+// returns masked values.
+type KeyValueMap struct {
+  Name       string      `json:"name,omitempty"`
+  Encrypted  bool        `json:"encrypted"`
+  Entries    Attributes  `json:"entry,omitempty"`
+}
+
+// KeyValueEntry holds a single entry within a Key Value Map.
+type KeyValueEntry struct {
+  Name   string   `json:"name,omitempty"`
+  Value  string   `json:"value,omitempty"`
+}
+
+// KeyListOptions holds optional parameters for paging through the keys of a
+// Key Value Map.
+type KeyListOptions struct {
+  // the maximum number of keys to return
+  Count    int     `url:"count,omitempty"`
+  // the key to start from; this key is included in the results
+  StartKey string  `url:"startkey,omitempty"`
+}
+
+// Environment returns a KeyValueMapsService that manages the Key Value Maps
+// scoped to the named environment.
+func (s *KeyValueMapsServiceOp) Environment(env string) KeyValueMapsService {
+  return &KeyValueMapsServiceOp{client: s.client, scope: path.Join("e", env)}
+}
+
+// Proxy returns a KeyValueMapsService that manages the Key Value Maps scoped to
+// the named API Proxy.
+func (s *KeyValueMapsServiceOp) Proxy(proxyName string) KeyValueMapsService {
+  return &KeyValueMapsServiceOp{client: s.client, scope: path.Join(apiUriPathElement, proxyName)}
+}
+
+func (s *KeyValueMapsServiceOp) kvmPath(elements ...string) string {
+  return path.Join(append([]string{s.scope, keyValueMapsPath}, elements...)...)
+}
+
+// newSensitiveRequest creates a request that is marked so that its body, and
+// the body of its response, are omitted from debug output.
+func (s *KeyValueMapsServiceOp) newSensitiveRequest(method, urlStr string, body interface{}) (*http.Request, error) {
+  req, e := s.client.NewRequest(method, urlStr, body)
+  if e != nil {
+    return nil, e
+  }
+  return markSensitive(req), nil
+}
+
+// List retrieves the names of the Key Value Maps in the scope.
+func (s *KeyValueMapsServiceOp) List() ([]string, *Response, error) {
+  req, e := s.client.NewRequest("GET", s.kvmPath(), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  namelist := make([]string,0)
+  resp, e := s.client.Do(req, &namelist)
+  if e != nil {
+    return nil, resp, e
+  }
+  return namelist, resp, e
+}
+
+// Get retrieves a Key Value Map, including its entries.
+func (s *KeyValueMapsServiceOp) Get(mapName string) (*KeyValueMap, *Response, error) {
+  req, e := s.newSensitiveRequest("GET", s.kvmPath(mapName), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedMap := KeyValueMap{}
+  resp, e := s.client.Do(req, &returnedMap)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedMap, resp, e
+}
+
+// Create creates a Key Value Map, with any entries it holds.
+func (s *KeyValueMapsServiceOp) Create(kvm KeyValueMap) (*KeyValueMap, *Response, error) {
+  if kvm.Name == "" {
+    return nil, nil, errors.New("cannot create a Key Value Map with no name")
+  }
+  req, e := s.newSensitiveRequest("POST", s.kvmPath(), kvm)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedMap := KeyValueMap{}
+  resp, e := s.client.Do(req, &returnedMap)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedMap, resp, e
+}
+
+// Update replaces the entries of a Key Value Map. On organizations that use
+// Core Persistence Services (CPS), Edge does not support updating a whole map;
+// use UpdateEntry instead.
+func (s *KeyValueMapsServiceOp) Update(kvm KeyValueMap) (*KeyValueMap, *Response, error) {
+  if kvm.Name == "" {
+    return nil, nil, errors.New("must specify the Name of the Key Value Map to update")
+  }
+  req, e := s.newSensitiveRequest("POST", s.kvmPath(kvm.Name), kvm)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedMap := KeyValueMap{}
+  resp, e := s.client.Do(req, &returnedMap)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedMap, resp, e
+}
+
+// Delete removes a Key Value Map and all its entries.
+func (s *KeyValueMapsServiceOp) Delete(mapName string) (*KeyValueMap, *Response, error) {
+  req, e := s.newSensitiveRequest("DELETE", s.kvmPath(mapName), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  deletedMap := KeyValueMap{}
+  resp, e := s.client.Do(req, &deletedMap)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &deletedMap, resp, e
+}
+
+// ListKeys retrieves the names of the entries in a Key Value Map. Use the
+// options to page through a map with many entries: pass the last key of one
+// page as the StartKey of the next.
+func (s *KeyValueMapsServiceOp) ListKeys(mapName string, opt *KeyListOptions) ([]string, *Response, error) {
+  keysPath, e := addOptions(s.kvmPath(mapName, "keys"), opt)
+  if e != nil {
+    return nil, nil, e
+  }
+  req, e := s.client.NewRequest("GET", keysPath, nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  namelist := make([]string,0)
+  resp, e := s.client.Do(req, &namelist)
+  if e != nil {
+    return nil, resp, e
+  }
+  return namelist, resp, e
+}
+
+// GetEntry retrieves one entry of a Key Value Map.
+func (s *KeyValueMapsServiceOp) GetEntry(mapName, entryName string) (*KeyValueEntry, *Response, error) {
+  req, e := s.newSensitiveRequest("GET", s.kvmPath(mapName, "entries", entryName), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedEntry := KeyValueEntry{}
+  resp, e := s.client.Do(req, &returnedEntry)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedEntry, resp, e
+}
+
+// CreateEntry adds an entry to a Key Value Map.
+func (s *KeyValueMapsServiceOp) CreateEntry(mapName string, entry KeyValueEntry) (*KeyValueEntry, *Response, error) {
+  if entry.Name == "" {
+    return nil, nil, errors.New("cannot create an entry with no name")
+  }
+  req, e := s.newSensitiveRequest("POST", s.kvmPath(mapName, "entries"), entry)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedEntry := KeyValueEntry{}
+  resp, e := s.client.Do(req, &returnedEntry)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedEntry, resp, e
+}
+
+// UpdateEntry changes the value of an existing entry in a Key Value Map.
+func (s *KeyValueMapsServiceOp) UpdateEntry(mapName string, entry KeyValueEntry) (*KeyValueEntry, *Response, error) {
+  if entry.Name == "" {
+    return nil, nil, errors.New("must specify the Name of the entry to update")
+  }
+  req, e := s.newSensitiveRequest("POST", s.kvmPath(mapName, "entries", entry.Name), entry)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedEntry := KeyValueEntry{}
+  resp, e := s.client.Do(req, &returnedEntry)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedEntry, resp, e
+}
+
+// DeleteEntry removes an entry from a Key Value Map.
+func (s *KeyValueMapsServiceOp) DeleteEntry(mapName, entryName string) (*KeyValueEntry, *Response, error) {
+  req, e := s.newSensitiveRequest("DELETE", s.kvmPath(mapName, "entries", entryName), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  deletedEntry := KeyValueEntry{}
+  resp, e := s.client.Do(req, &deletedEntry)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &deletedEntry, resp, e
+}
+
+// readKeyValueFile reads entries from a JSON file. The file may hold either a
+// plain object, like {"key1": "value1", "key2": "value2"}, or a map in the form
+// Edge uses, like {"name": "map1", "entry": [{"name": "key1", "value": "value1"}]}.
+func readKeyValueFile(filename string) (Attributes, error) {
+  data, e := ioutil.ReadFile(filename)
+  if e != nil {
+    return nil, e
+  }
+  var edgeForm struct {
+    Entries json.RawMessage `json:"entry"`
+  }
+  if e := json.Unmarshal(data, &edgeForm); e == nil && len(edgeForm.Entries) > 0 {
+    var entries []KeyValueEntry
+    e = json.Unmarshal(edgeForm.Entries, &entries)
+    if e != nil {
+      return nil, e
+    }
+    attrs := Attributes{}
+    for _, entry := range entries {
+      attrs[entry.Name] = entry.Value
+    }
+    return attrs, nil
+  }
+  attrs := Attributes{}
+  var plain map[string]string
+  e = json.Unmarshal(data, &plain)
+  if e != nil {
+    return nil, e
+  }
+  for k, v := range plain {
+    attrs[k] = v
+  }
+  return attrs, nil
+}
+
+// LoadFromFile loads the entries in a local JSON file into a Key Value Map. If
+// the map does not exist, it is created, with the given encrypted flag. If it
+// exists, each entry in the file is updated, or created if absent; entries not
+// in the file are left unchanged.
+func (s *KeyValueMapsServiceOp) LoadFromFile(mapName, filename string, encrypted bool) (*KeyValueMap, error) {
+  entries, e := readKeyValueFile(filename)
+  if e != nil {
+    return nil, e
+  }
+  _, _, e = s.Get(mapName)
+  if e != nil {
+    if !isNotFound(e) {
+      return nil, e
+    }
+    created, _, e := s.Create(KeyValueMap{Name: mapName, Encrypted: encrypted, Entries: entries})
+    return created, e
+  }
+
+  // apply entries in a predictable order
+  keys := make([]string, 0, len(entries))
+  for k := range entries {
+    keys = append(keys, k)
+  }
+  sort.Strings(keys)
+  for _, k := range keys {
+    entry := KeyValueEntry{Name: k, Value: entries[k]}
+    _, _, e := s.UpdateEntry(mapName, entry)
+    if e != nil && isNotFound(e) {
+      _, _, e = s.CreateEntry(mapName, entry)
+    }
+    if e != nil {
+      return nil, e
+    }
+  }
+  updated, _, e := s.Get(mapName)
+  return updated, e
+}
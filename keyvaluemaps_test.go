@@ -0,0 +1,105 @@
+package apigee
+
+import (
+  "encoding/json"
+  "io/ioutil"
+  "net/url"
+  "os"
+  "path"
+  "testing"
+)
+
+const (
+  kvmJson1 = `{
+  "encrypted" : true,
+  "entry" : [ {
+    "name" : "password",
+    "value" : "*****"
+  }, {
+    "name" : "username",
+    "value" : "*****"
+  } ],
+  "name" : "credentials"
+}`
+  kvmFile1 = `{ "backend" : "https://backend.example.com", "timeout" : "30" }`
+  kvmFile2 = `{
+  "name" : "settings",
+  "entry" : [ { "name" : "backend", "value" : "https://backend.example.com" },
+              { "name" : "timeout", "value" : "30" } ]
+}`
+)
+
+func TestKeyValueMap_Unmarshal(t *testing.T) {
+  var got KeyValueMap
+  e := json.Unmarshal([]byte(kvmJson1), &got)
+  if e != nil {
+    t.Errorf("while unmarshaling, error:\n%#v\n", e)
+    return
+  }
+  expected := KeyValueMap{Name: "credentials", Encrypted: true, Entries: Attributes{"password": "*****", "username": "*****"}}
+  if got.Name != expected.Name || got.Encrypted != expected.Encrypted || got.Entries.String() != expected.Entries.String() {
+    t.Errorf("got=%#v, expected=%#v", got, expected)
+  }
+}
+
+func TestKeyValueMap_ReadFile(t *testing.T) {
+  tempDir, e := ioutil.TempDir("", "go-apigee-test-")
+  if e != nil {
+    t.Errorf("while creating temp dir, error:\n%#v\n", e)
+    return
+  }
+  defer os.RemoveAll(tempDir)
+
+  expected := Attributes{"backend": "https://backend.example.com", "timeout": "30"}
+  for i, content := range []string{kvmFile1, kvmFile2} {
+    filename := path.Join(tempDir, "kvm.json")
+    e = ioutil.WriteFile(filename, []byte(content), 0644)
+    if e != nil {
+      t.Errorf("while writing file, error:\n%#v\n", e)
+      return
+    }
+    got, e := readKeyValueFile(filename)
+    if e != nil {
+      t.Errorf("file %d: error=%v", i, e)
+      continue
+    }
+    if got.String() != expected.String() {
+      t.Errorf("file %d: got=%v, expected=%v", i, got, expected)
+    }
+  }
+}
+
+func TestKeyValueMap_Scopes(t *testing.T) {
+  kvms := &KeyValueMapsServiceOp{}
+  testCases := []struct {
+    got      string
+    expected string
+  }{
+    {kvms.kvmPath("m1"), "keyvaluemaps/m1"},
+    {kvms.Environment("test").(*KeyValueMapsServiceOp).kvmPath("m1", "entries", "k1"), "e/test/keyvaluemaps/m1/entries/k1"},
+    {kvms.Proxy("flights").(*KeyValueMapsServiceOp).kvmPath(), "apis/flights/keyvaluemaps"},
+  }
+  for _, tc := range testCases {
+    if tc.got != tc.expected {
+      t.Errorf("got=%s, expected=%s", tc.got, tc.expected)
+    }
+  }
+}
+
+func TestKeyValueMap_SensitiveRequests(t *testing.T) {
+  baseURL, _ := url.Parse("https://api.enterprise.apigee.com/v1/o/org1")
+  client := &ApigeeClient{BaseURL: baseURL, auth: &AdminAuth{}}
+  kvms := &KeyValueMapsServiceOp{client: client}
+  req, e := kvms.newSensitiveRequest("GET", kvms.kvmPath("m1"), nil)
+  if e != nil {
+    t.Errorf("while creating request, error:\n%#v\n", e)
+    return
+  }
+  if !isSensitive(req) {
+    t.Errorf("expected request to be marked sensitive")
+  }
+  plain, _ := client.NewRequest("GET", kvms.kvmPath(), nil)
+  if isSensitive(plain) {
+    t.Errorf("expected request not to be marked sensitive")
+  }
+}
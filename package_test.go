@@ -3,6 +3,8 @@ package apigee
 import (
   "testing"
   "fmt"
+  "net/http"
+  "net/http/httptest"
 	"encoding/json"
 	"io/ioutil"
 	"time"
@@ -46,6 +48,19 @@ func NewClientForTesting(t *testing.T) *ApigeeClient {
 	return client
 }
 
+// newStubClient returns a client that sends its requests to the handler
+// instead of Edge, and a func that stops the stub server.
+func newStubClient(t *testing.T, handler http.HandlerFunc) (*ApigeeClient, func()) {
+  server := httptest.NewServer(handler)
+  opts := &ApigeeClientOptions{MgmtUrl: server.URL, Org: "org1", Auth: &AdminAuth{Username: "user", Password: "secret"}}
+  client, e := NewApigeeClient(opts)
+  if e != nil {
+    server.Close()
+    t.Fatalf("while initializing stub client, error: %v", e)
+  }
+  return client, server.Close
+}
+
 func wait(delay int) {
   fmt.Printf("Waiting %ds...\n", delay)
   time.Sleep(time.Duration(delay)*time.Second)
@@ -3,6 +3,8 @@ package apigee
 import (
   "path"
   "errors"
+  "fmt"
+  "strconv"
 )
 
 const productsPath = "apiproducts"
@@ -15,6 +17,10 @@ type ProductsService interface {
   Create(ApiProduct) (*ApiProduct, *Response, error)
   Update(ApiProduct) (*ApiProduct, *Response, error)
   Delete(string) (*ApiProduct, *Response, error)
+  GetAttribute(string, string) (*Attribute, *Response, error)
+  SetAttribute(string, Attribute) (*Attribute, *Response, error)
+  DeleteAttribute(string, string) (*Attribute, *Response, error)
+  ReplaceAttributes(string, Attributes) (Attributes, *Response, error)
 }
 
 type ProductsServiceOp struct {
@@ -38,9 +44,138 @@ type ApiProduct struct {
   Environments    []string    `json:"environments,omitempty"`
   Proxies         []string    `json:"proxies,omitempty"`
   Scopes          []string    `json:"scopes,omitempty"`
+  Quota           string      `json:"quota,omitempty"`
+  QuotaInterval   string      `json:"quotaInterval,omitempty"`
+  QuotaTimeUnit   string      `json:"quotaTimeUnit,omitempty"`
+  OperationGroup         *OperationGroup         `json:"operationGroup,omitempty"`
+  GraphqlOperationGroup  *GraphqlOperationGroup  `json:"graphqlOperationGroup,omitempty"`
+  // fields returned by Edge that are not modeled above; sent back on Update
+  Extra           ExtraFields `json:"-"`
+}
+
+// QuotaTimeUnits lists the valid values for the time unit of a quota.
+var QuotaTimeUnits = []string{"minute", "hour", "day", "month"}
+
+// OperationGroup associates REST operations on API Proxies or remote services
+// with an API Product, optionally with a quota and attributes for each.
+type OperationGroup struct {
+  // "proxy" or "remoteservice"
+  OperationConfigType  string             `json:"operationConfigType,omitempty"`
+  OperationConfigs     []OperationConfig  `json:"operationConfigs,omitempty"`
+}
+
+// OperationConfig binds the operations of one API source to a quota and attributes.
+type OperationConfig struct {
+  ApiSource   string            `json:"apiSource,omitempty"`
+  Operations  []Operation       `json:"operations,omitempty"`
+  Quota       *OperationQuota   `json:"quota,omitempty"`
+  Attributes  Attributes        `json:"attributes,omitempty"`
+}
+
+// Operation identifies a resource path and the HTTP methods allowed on it.
+type Operation struct {
+  Resource  string    `json:"resource,omitempty"`
+  Methods   []string  `json:"methods,omitempty"`
+}
+
+// GraphqlOperationGroup associates GraphQL operations on API Proxies with an
+// API Product, optionally with a quota and attributes for each.
+type GraphqlOperationGroup struct {
+  OperationConfigType  string                    `json:"operationConfigType,omitempty"`
+  OperationConfigs     []GraphqlOperationConfig  `json:"operationConfigs,omitempty"`
+}
+
+// GraphqlOperationConfig binds the GraphQL operations of one API source to a
+// quota and attributes.
+type GraphqlOperationConfig struct {
+  ApiSource   string              `json:"apiSource,omitempty"`
+  Operations  []GraphqlOperation  `json:"operations,omitempty"`
+  Quota       *OperationQuota     `json:"quota,omitempty"`
+  Attributes  Attributes          `json:"attributes,omitempty"`
+}
+
+// GraphqlOperation identifies a GraphQL operation by its types, like QUERY or
+// MUTATION, and optionally its name.
+type GraphqlOperation struct {
+  OperationTypes  []string  `json:"operationTypes,omitempty"`
+  Operation       string    `json:"operation,omitempty"`
+}
+
+// OperationQuota holds the quota for an operation config. It overrides the
+// quota of the API Product.
+type OperationQuota struct {
+  Limit     string  `json:"limit,omitempty"`
+  Interval  string  `json:"interval,omitempty"`
+  TimeUnit  string  `json:"timeUnit,omitempty"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Fields that
+// ApiProduct does not model are kept in Extra.
+func (p *ApiProduct) UnmarshalJSON(data []byte) error {
+  type plain ApiProduct
+  return unmarshalWithExtra(data, (*plain)(p), &p.Extra)
+}
+
+// MarshalJSON implements the json.Marshaler interface, including the Extra fields.
+func (p ApiProduct) MarshalJSON() ([]byte, error) {
+  type plain ApiProduct
+  return marshalWithExtra(plain(p), p.Extra)
+}
+
+func validateQuota(context, limit, interval, timeUnit string) error {
+  if limit == "" && interval == "" && timeUnit == "" {
+    return nil
+  }
+  if limit == "" || interval == "" || timeUnit == "" {
+    return fmt.Errorf("%s: quota, interval and time unit must be set together", context)
+  }
+  for _, v := range []string{limit, interval} {
+    n, e := strconv.Atoi(v)
+    if e != nil || n <= 0 {
+      return fmt.Errorf("%s: %q is not a positive integer", context, v)
+    }
+  }
+  for _, unit := range QuotaTimeUnits {
+    if timeUnit == unit {
+      return nil
+    }
+  }
+  return fmt.Errorf("%s: quota time unit %q must be one of %v", context, timeUnit, QuotaTimeUnits)
+}
+
+// Validate checks the quota settings of the product and of each operation config.
+func (p ApiProduct) Validate() error {
+  e := validateQuota("product " + p.Name, p.Quota, p.QuotaInterval, p.QuotaTimeUnit)
+  if e != nil {
+    return e
+  }
+  if p.OperationGroup != nil {
+    for _, c := range p.OperationGroup.OperationConfigs {
+      if c.Quota != nil {
+        e = validateQuota("operation config " + c.ApiSource, c.Quota.Limit, c.Quota.Interval, c.Quota.TimeUnit)
+        if e != nil {
+          return e
+        }
+      }
+    }
+  }
+  if p.GraphqlOperationGroup != nil {
+    for _, c := range p.GraphqlOperationGroup.OperationConfigs {
+      if c.Quota != nil {
+        e = validateQuota("graphql operation config " + c.ApiSource, c.Quota.Limit, c.Quota.Interval, c.Quota.TimeUnit)
+        if e != nil {
+          return e
+        }
+      }
+    }
+  }
+  return nil
 }
 
 func reallyUpdateProduct(s ProductsServiceOp, product ApiProduct) (*ApiProduct, *Response, error) {
+  if e := product.Validate(); e != nil {
+    return nil, nil, e
+  }
   path := path.Join(productsPath, product.Name)
   req, e := s.client.NewRequest("POST", path, product)
   if e != nil {
@@ -60,7 +195,7 @@ func (s *ProductsServiceOp) Update(product ApiProduct) (*ApiProduct, *Response,
     return nil, nil, errors.New("must specify Name of ApiProduct to update")
 	}
 
-	if product.ApprovalType == "" || product.DisplayName == "" || product.Environments == nil {
+	if product.ApprovalType == "" || product.DisplayName == "" || product.Environments == nil || product.Extra == nil {
 		// The request is lacking some required information.
 		// Must get the apiproduct first, to fill in these "required" parameters.
     retrievedProduct, resp, e := s.Get(product.Name)
@@ -76,6 +211,10 @@ func (s *ProductsServiceOp) Update(product ApiProduct) (*ApiProduct, *Response,
 		if product.Environments == nil {
 			product.Environments = retrievedProduct.Environments
 		}
+		if product.Extra == nil {
+			// keep the settings that this library does not model
+			product.Extra = retrievedProduct.Extra
+		}
 	}
 
 	// We have all required information...
@@ -87,6 +226,9 @@ func (s *ProductsServiceOp) Update(product ApiProduct) (*ApiProduct, *Response,
 
 
 func (s *ProductsServiceOp) Create(product ApiProduct) (*ApiProduct, *Response, error) {
+  if e := product.Validate(); e != nil {
+    return nil, nil, e
+  }
   req, e := s.client.NewRequest("POST", productsPath, product)
   if e != nil {
     return nil, nil, e
@@ -2,6 +2,7 @@ package apigee
 
 import (
   "encoding/json"
+  "strings"
   "testing"
 	"math/rand"
 )
@@ -78,3 +79,136 @@ func TestProductCreateDelete(t *testing.T) {
   }
 	t.Logf("Delete: got=%v", deletedProduct)
 }
+
+const (
+  productQuotaJson1 = `{
+  "apiResources" : [ "/**" ],
+  "approvalType" : "manual",
+  "attributes" : [ { "name" : "access", "value" : "public" } ],
+  "createdAt" : 1500000000000,
+  "createdBy" : "admin@example.com",
+  "displayName" : "Flights Premium",
+  "environments" : [ "test", "prod" ],
+  "lastModifiedAt" : 1600000000000,
+  "lastModifiedBy" : "admin@example.com",
+  "name" : "flights-premium",
+  "proxies" : [ "flights" ],
+  "quota" : "1000",
+  "quotaInterval" : "1",
+  "quotaTimeUnit" : "month",
+  "scopes" : [ "read", "write" ],
+  "operationGroup" : {
+    "operationConfigType" : "proxy",
+    "operationConfigs" : [ {
+      "apiSource" : "flights",
+      "operations" : [ { "resource" : "/status", "methods" : [ "GET" ] } ],
+      "quota" : { "limit" : "10", "interval" : "1", "timeUnit" : "minute" },
+      "attributes" : [ { "name" : "tier", "value" : "gold" } ]
+    } ]
+  },
+  "graphqlOperationGroup" : {
+    "operationConfigType" : "proxy",
+    "operationConfigs" : [ {
+      "apiSource" : "flights-graphql",
+      "operations" : [ { "operationTypes" : [ "QUERY" ], "operation" : "flightStatus" } ],
+      "quota" : { "limit" : "5", "interval" : "1", "timeUnit" : "hour" }
+    } ]
+  },
+  "quotaCounterScope" : "PROXY",
+  "futureSetting" : { "enabled" : true }
+}`
+)
+
+func TestProductQuota_Unmarshal(t *testing.T) {
+  var got ApiProduct
+  e := json.Unmarshal([]byte(productQuotaJson1), &got)
+  if e != nil {
+    t.Errorf("while unmarshaling, error:\n%#v\n", e)
+    return
+  }
+  if got.Quota != "1000" || got.QuotaInterval != "1" || got.QuotaTimeUnit != "month" {
+    t.Errorf("quota: got=%#v", got)
+  }
+  if got.OperationGroup == nil || len(got.OperationGroup.OperationConfigs) != 1 {
+    t.Errorf("operationGroup: got=%#v", got.OperationGroup)
+    return
+  }
+  config := got.OperationGroup.OperationConfigs[0]
+  if config.ApiSource != "flights" || config.Operations[0].Resource != "/status" ||
+    config.Operations[0].Methods[0] != "GET" || config.Quota.TimeUnit != "minute" ||
+    config.Attributes["tier"] != "gold" {
+    t.Errorf("operationConfig: got=%#v", config)
+  }
+  if got.GraphqlOperationGroup == nil || got.GraphqlOperationGroup.OperationConfigs[0].Operations[0].Operation != "flightStatus" {
+    t.Errorf("graphqlOperationGroup: got=%#v", got.GraphqlOperationGroup)
+  }
+  if len(got.Extra) != 2 || string(got.Extra["quotaCounterScope"]) != `"PROXY"` {
+    t.Errorf("extra: got=%v", got.Extra)
+  }
+  if e := got.Validate(); e != nil {
+    t.Errorf("unexpected validation error: %v", e)
+  }
+}
+
+func TestProductQuota_RoundTrip(t *testing.T) {
+  var original ApiProduct
+  e := json.Unmarshal([]byte(productQuotaJson1), &original)
+  if e != nil {
+    t.Errorf("while unmarshaling, error:\n%#v\n", e)
+    return
+  }
+  original.Quota = "2000"
+  data, e := json.Marshal(original)
+  if e != nil {
+    t.Errorf("while marshaling, error:\n%#v\n", e)
+    return
+  }
+  var expected, got map[string]interface{}
+  json.Unmarshal([]byte(productQuotaJson1), &expected)
+  json.Unmarshal(data, &got)
+  expected["quota"] = "2000"
+  for k, v := range expected {
+    if !jsonEqual(v, got[k]) {
+      t.Errorf("%s: got=%v, expected=%v", k, got[k], v)
+    }
+  }
+  if len(got) != len(expected) {
+    t.Errorf("got %d fields, expected %d", len(got), len(expected))
+  }
+}
+
+func jsonEqual(a, b interface{}) bool {
+  ja, _ := json.Marshal(a)
+  jb, _ := json.Marshal(b)
+  return string(ja) == string(jb)
+}
+
+func TestProductValidate(t *testing.T) {
+  testCases := []struct {
+    product ApiProduct
+    errText string
+  }{
+    {ApiProduct{Name: "p"}, ""},
+    {ApiProduct{Name: "p", Quota: "10", QuotaInterval: "1", QuotaTimeUnit: "hour"}, ""},
+    {ApiProduct{Name: "p", Quota: "10", QuotaInterval: "1", QuotaTimeUnit: "week"}, "must be one of"},
+    {ApiProduct{Name: "p", Quota: "10", QuotaTimeUnit: "day"}, "set together"},
+    {ApiProduct{Name: "p", Quota: "ten", QuotaInterval: "1", QuotaTimeUnit: "day"}, "not a positive integer"},
+    {ApiProduct{Name: "p", QuotaInterval: "0", Quota: "5", QuotaTimeUnit: "day"}, "not a positive integer"},
+    {ApiProduct{Name: "p", OperationGroup: &OperationGroup{OperationConfigs: []OperationConfig{
+      {ApiSource: "flights", Quota: &OperationQuota{"10", "1", "seconds"}}}}}, "operation config flights"},
+    {ApiProduct{Name: "p", GraphqlOperationGroup: &GraphqlOperationGroup{OperationConfigs: []GraphqlOperationConfig{
+      {ApiSource: "gql", Quota: &OperationQuota{"10", "", "day"}}}}}, "graphql operation config gql"},
+  }
+  for i, tc := range testCases {
+    e := tc.product.Validate()
+    if tc.errText == "" {
+      if e != nil {
+        t.Errorf("case %d: unexpected error: %v", i, e)
+      }
+      continue
+    }
+    if e == nil || !strings.Contains(e.Error(), tc.errText) {
+      t.Errorf("case %d: got=%v, expected error containing %q", i, e, tc.errText)
+    }
+  }
+}
@@ -0,0 +1,183 @@
+package apigee
+
+import (
+  "fmt"
+  "time"
+)
+
+var (
+  // DeploymentWaitTimeout is the maximum time Promote and Rollback wait for a
+  // deployment to become healthy on all servers.
+  DeploymentWaitTimeout = 5 * time.Minute
+
+  // DeploymentPollInterval is the interval between checks of deployment status
+  // while waiting for a deployment to complete.
+  DeploymentPollInterval = 5 * time.Second
+)
+
+// PromoteResult describes the outcome of promoting a revision of an API Proxy
+// or SharedFlow from one environment to another.
+type PromoteResult struct {
+  Name             string
+  FromEnv          string
+  ToEnv            string
+  // the revision deployed into ToEnv
+  Revision         Revision
+  // the revision previously deployed in ToEnv, or 0 if there was none
+  PreviousRevision Revision
+  BasePath         string
+  // true if the deployment failed and the previous revision was redeployed
+  RolledBack       bool
+}
+
+// deployedInEnv returns the deployments of the asset in the named environment,
+// or nil if there are none.
+func deployedInEnv(deployment *Deployment, env string) []RevisionDeployment {
+  for _, envDeployment := range deployment.Environments {
+    if envDeployment.Name == env {
+      return envDeployment.Revision
+    }
+  }
+  return nil
+}
+
+// newestDeployed returns the newest revision in the list that is in the
+// "deployed" state, or nil if there is none.
+func newestDeployed(revisions []RevisionDeployment) *RevisionDeployment {
+  var newest *RevisionDeployment
+  for i := range revisions {
+    if revisions[i].State != "deployed" {
+      continue
+    }
+    if newest == nil || revisions[i].Number > newest.Number {
+      newest = &revisions[i]
+    }
+  }
+  return newest
+}
+
+// deploymentHealth reports whether the revision deployment is complete on all
+// servers, and returns an error if Edge reports that it failed.
+func deploymentHealth(d RevisionDeployment) (bool, error) {
+  if d.State == "error" {
+    return false, fmt.Errorf("revision %d is in state error", d.Number)
+  }
+  for _, server := range d.Servers {
+    if server.Status == "error" {
+      return false, fmt.Errorf("revision %d failed on server %s", d.Number, server.Uuid)
+    }
+  }
+  if d.State != "deployed" {
+    return false, nil
+  }
+  for _, server := range d.Servers {
+    if server.Status != "deployed" {
+      return false, nil
+    }
+  }
+  return true, nil
+}
+
+// waitForDeployment polls the deployment status of the asset until the revision
+// is deployed on all servers in the environment, until Edge reports an error,
+// or until DeploymentWaitTimeout elapses.
+func (s *Deployable) waitForDeployment(client *ApigeeClient, uriPathElement, assetName, env string, rev Revision) error {
+  deadline := time.Now().Add(DeploymentWaitTimeout)
+  for {
+    deployment, _, e := s.GetDeployments(client, uriPathElement, assetName)
+    if e != nil {
+      return e
+    }
+    for _, d := range deployedInEnv(deployment, env) {
+      if d.Number == rev {
+        healthy, e := deploymentHealth(d)
+        if e != nil {
+          return e
+        }
+        if healthy {
+          return nil
+        }
+      }
+    }
+    if time.Now().After(deadline) {
+      return fmt.Errorf("timed out waiting for %s revision %d to deploy in %s", assetName, rev, env)
+    }
+    time.Sleep(DeploymentPollInterval)
+  }
+}
+
+// undeployOthers undeploys from the environment every revision other than the
+// one to keep. With override, Edge normally does this itself; this catches
+// revisions left behind.
+func (s *Deployable) undeployOthers(client *ApigeeClient, uriPathElement, assetName, env string, keep Revision) error {
+  deployment, _, e := s.GetDeployments(client, uriPathElement, assetName)
+  if e != nil {
+    return e
+  }
+  for _, d := range deployedInEnv(deployment, env) {
+    if d.Number != keep {
+      _, _, e := s.Undeploy(client, uriPathElement, assetName, env, d.Number)
+      if e != nil {
+        return e
+      }
+    }
+  }
+  return nil
+}
+
+// Promote deploys into toEnv the revision of an API Proxy or SharedFlow that is
+// currently deployed in fromEnv, then undeploys the revision previously deployed
+// in toEnv. The basepath of the existing deployment in toEnv is preserved; if
+// there is none, the basepath used in fromEnv applies. If the new deployment
+// does not become healthy, Promote redeploys the previous revision, and waits
+// for that to become healthy in turn.
+func (s *Deployable) Promote(client *ApigeeClient, uriPathElement, assetName, fromEnv, toEnv string) (*PromoteResult, error) {
+  if fromEnv == toEnv {
+    return nil, fmt.Errorf("cannot promote from %s to itself", fromEnv)
+  }
+  deployment, _, e := s.GetDeployments(client, uriPathElement, assetName)
+  if e != nil {
+    return nil, e
+  }
+  source := newestDeployed(deployedInEnv(deployment, fromEnv))
+  if source == nil {
+    return nil, fmt.Errorf("%s is not deployed in %s", assetName, fromEnv)
+  }
+
+  result := &PromoteResult{Name: assetName, FromEnv: fromEnv, ToEnv: toEnv, Revision: source.Number, BasePath: source.BasePath()}
+  previous := newestDeployed(deployedInEnv(deployment, toEnv))
+  if previous != nil {
+    result.PreviousRevision = previous.Number
+    if previous.BasePath() != "" {
+      result.BasePath = previous.BasePath()
+    }
+    if previous.Number == source.Number {
+      // nothing to do
+      return result, nil
+    }
+  }
+
+  _, _, e = s.Deploy(client, uriPathElement, assetName, result.BasePath, toEnv, result.Revision)
+  if e == nil {
+    e = s.waitForDeployment(client, uriPathElement, assetName, toEnv, result.Revision)
+  }
+  if e != nil {
+    if previous == nil {
+      _, _, _ = s.Undeploy(client, uriPathElement, assetName, toEnv, result.Revision)
+      return result, fmt.Errorf("promoting %s revision %d to %s: %v", assetName, result.Revision, toEnv, e)
+    }
+    _, _, rbErr := s.Deploy(client, uriPathElement, assetName, previous.BasePath(), toEnv, previous.Number)
+    if rbErr == nil {
+      rbErr = s.waitForDeployment(client, uriPathElement, assetName, toEnv, previous.Number)
+    }
+    if rbErr != nil {
+      return result, fmt.Errorf("promoting %s revision %d to %s: %v; rollback to revision %d also failed: %v",
+        assetName, result.Revision, toEnv, e, previous.Number, rbErr)
+    }
+    result.RolledBack = true
+    return result, fmt.Errorf("promoting %s revision %d to %s: %v; rolled back to revision %d",
+      assetName, result.Revision, toEnv, e, previous.Number)
+  }
+
+  return result, s.undeployOthers(client, uriPathElement, assetName, toEnv, result.Revision)
+}
@@ -0,0 +1,124 @@
+package apigee
+
+import (
+  "encoding/json"
+  "fmt"
+  "strings"
+  "testing"
+  "time"
+)
+
+const (
+  deploymentJson1 = `{
+  "environment" : [ {
+    "name" : "test",
+    "revision" : [ {
+      "configuration" : { "basePath" : "/v1/flights", "steps" : [ ] },
+      "name" : "4",
+      "server" : [ { "status" : "deployed", "type" : [ "message-processor" ], "uUID" : "a1" } ],
+      "state" : "deployed"
+    } ]
+  }, {
+    "name" : "prod",
+    "revision" : [ {
+      "configuration" : { "basePath" : "/", "steps" : [ ] },
+      "name" : "2",
+      "server" : [ { "status" : "deployed", "type" : [ "message-processor" ], "uUID" : "b1" },
+                   { "status" : "undeployed", "type" : [ "message-processor" ], "uUID" : "b2" } ],
+      "state" : "deployed"
+    }, {
+      "name" : "3",
+      "server" : [ { "status" : "error", "type" : [ "message-processor" ], "uUID" : "b3" } ],
+      "state" : "error"
+    } ]
+  } ],
+  "name" : "flights",
+  "organization" : "cheeso"
+}`
+)
+
+func TestDeployment_Unmarshal(t *testing.T) {
+  var got Deployment
+  e := json.Unmarshal([]byte(deploymentJson1), &got)
+  if e != nil {
+    t.Errorf("while unmarshaling, error:\n%#v\n", e)
+    return
+  }
+
+  test := newestDeployed(deployedInEnv(&got, "test"))
+  if test == nil || test.Number != 4 || test.BasePath() != "/v1/flights" {
+    t.Errorf("test: got=%#v", test)
+  }
+  prod := newestDeployed(deployedInEnv(&got, "prod"))
+  if prod == nil || prod.Number != 2 || prod.BasePath() != "/" {
+    t.Errorf("prod: got=%#v", prod)
+  }
+  if none := newestDeployed(deployedInEnv(&got, "staging")); none != nil {
+    t.Errorf("staging: got=%#v, expected nil", none)
+  }
+}
+
+func TestDeploymentHealth(t *testing.T) {
+  var got Deployment
+  e := json.Unmarshal([]byte(deploymentJson1), &got)
+  if e != nil {
+    t.Errorf("while unmarshaling, error:\n%#v\n", e)
+    return
+  }
+  testCases := []struct {
+    desc       string
+    deployment RevisionDeployment
+    healthy    bool
+    wantErr    bool
+  }{
+    {"all servers deployed", deployedInEnv(&got, "test")[0], true, false},
+    {"one server pending", deployedInEnv(&got, "prod")[0], false, false},
+    {"error", deployedInEnv(&got, "prod")[1], false, true},
+  }
+  for _, tc := range testCases {
+    healthy, e := deploymentHealth(tc.deployment)
+    if gotErr := e != nil; gotErr != tc.wantErr {
+      t.Errorf("%s: gotErr=%v, wantErr=%v, err=%v", tc.desc, gotErr, tc.wantErr, e)
+    }
+    if healthy != tc.healthy {
+      t.Errorf("%s: healthy[got=%v, expected=%v]", tc.desc, healthy, tc.healthy)
+    }
+  }
+}
+
+func TestPromote_DeployFails(t *testing.T) {
+  saved := DeploymentPollInterval
+  DeploymentPollInterval = time.Millisecond
+  defer func() { DeploymentPollInterval = saved }()
+
+  testCases := []struct {
+    desc        string
+    prod        []Revision
+    rolledBack  bool
+    changes     string
+  }{
+    {"previous revision", []Revision{3}, true, "deploy 4 prod, deploy 3 prod"},
+    {"first deploy", nil, false, "deploy 4 prod, undeploy 4 prod"},
+  }
+  for _, tc := range testCases {
+    stub := &deploymentsStub{
+      deployed: map[string][]Revision{"test": {4}, "prod": tc.prod},
+      failing: map[Revision]bool{4: true},
+    }
+    client, stop := newStubClient(t, stub.ServeHTTP)
+    result, e := client.Proxies.Promote("flights", "test", "prod")
+    stop()
+    if e == nil {
+      t.Errorf("%s: expected an error", tc.desc)
+    }
+    if result == nil || result.RolledBack != tc.rolledBack {
+      t.Errorf("%s: got=%#v, expected RolledBack=%v", tc.desc, result, tc.rolledBack)
+    }
+    if changes := strings.Join(stub.changes, ", "); changes != tc.changes {
+      t.Errorf("%s: changes got=%q, expected=%q", tc.desc, changes, tc.changes)
+    }
+    if got, expected := fmt.Sprintf("%v", stub.deployed["prod"]), fmt.Sprintf("%v", tc.prod); got != expected {
+      t.Errorf("%s: prod got=%s, expected=%s", tc.desc, got, expected)
+    }
+  }
+}
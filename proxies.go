@@ -1,5 +1,9 @@
 package apigee
 
+import (
+  "io"
+)
+
 const uriPathElement = "apis"
 
 // ProxiesService is an interface for interfacing with the Apigee Admin API
@@ -8,6 +12,7 @@ type ProxiesService interface {
   List() ([]string, *Response, error)
   Get(string) (*DeployableAsset, *Response, error)
   Import(string, string) (*DeployableRevision, *Response, error)
+  ImportIfChanged(string, string) (*DeployableRevision, *Response, error)
   Delete(string) (*DeletedItemInfo, *Response, error)
   DeleteRevision(string, Revision) (*DeployableRevision, *Response, error)
   Deploy(string,string,Revision) (*RevisionDeployment, *Response, error)
@@ -15,6 +20,24 @@ type ProxiesService interface {
   Undeploy(string,string,Revision) (*RevisionDeployment, *Response, error)
   Export(string, Revision) (string, *Response, error)
   GetDeployments(string) (*Deployment, *Response, error)
+  PruneRevisions(string, PruneOptions) (*PruneReport, error)
+  PruneAll(PruneOptions) ([]PruneReport, error)
+  Promote(string, string, string) (*PromoteResult, error)
+  Rollback(string, string) (*RollbackResult, error)
+  RollbackTo(string, string, Revision) (*RollbackResult, error)
+  DeploymentHistory(string, string) []Revision
+  GetRevision(string, Revision) (*DeployableRevision, *Response, error)
+  ListPolicies(string, Revision) ([]string, *Response, error)
+  GetPolicy(string, Revision, string) (string, *Response, error)
+  ListResourceFiles(string, Revision) ([]ResourceFile, *Response, error)
+  GetResourceFile(string, Revision, string, string) ([]byte, *Response, error)
+  CreateResourceFile(string, Revision, string, string, io.Reader) (*ResourceFile, *Response, error)
+  UpdateResourceFile(string, Revision, string, string, io.Reader) (*ResourceFile, *Response, error)
+  DeleteResourceFile(string, Revision, string, string) (*ResourceFile, *Response, error)
+  GetProxyEndpoint(string, Revision, string) (string, *Response, error)
+  GetTargetEndpoint(string, Revision, string) (string, *Response, error)
+  CheckConflicts(string, string, string, Revision) ([]BasePathConflict, error)
+  CheckBundleConflicts(string, string, string, string) ([]BasePathConflict, error)
 }
 
 type ProxiesServiceOp struct {
@@ -59,6 +82,15 @@ func (s *ProxiesServiceOp) Import(proxyName string, source string) (*DeployableR
 	return s.deployable.Import(s.client, uriPathElement, proxyName, source)
 }
 
+// ImportIfChanged imports an API proxy into an organization, creating a new API
+// Proxy revision, only if the bundle content differs from the latest existing
+// revision. The comparison uses the fingerprint computed by BundleFingerprint.
+// When the content is unchanged, nothing is uploaded, and the returned revision
+// information describes the existing latest revision.
+func (s *ProxiesServiceOp) ImportIfChanged(proxyName string, source string) (*DeployableRevision, *Response, error) {
+	return s.deployable.ImportIfChanged(s.client, uriPathElement, proxyName, source)
+}
+
 // Export a revision of an API proxy within an organization, to a filesystem file.
 func (s *ProxiesServiceOp) Export(proxyName string, rev Revision) (string, *Response, error) {
 	return s.deployable.Export(s.client, uriPathElement, proxyName, rev)
@@ -85,6 +117,28 @@ func (s *ProxiesServiceOp) DeployAtPath(proxyName, basepath, env string, rev Rev
 	return s.deployable.Deploy(s.client, uriPathElement, proxyName, basepath, env, rev)
 }
 
+// CheckConflicts reports the ProxyEndpoints of a revision of an API Proxy that,
+// if the revision were deployed into env at the given basepath, would listen at
+// the same basepath, on the same virtual host, as another API Proxy already
+// deployed there. Pass an empty basepath for a plain Deploy.
+func (s *ProxiesServiceOp) CheckConflicts(proxyName, basepath, env string, rev Revision) ([]BasePathConflict, error) {
+  candidate, e := proxyConnections(s.client, proxyName, rev)
+  if e != nil {
+    return nil, e
+  }
+  return checkConflicts(s.client, proxyName, candidate, basepath, env)
+}
+
+// CheckBundleConflicts is like CheckConflicts, but examines a local bundle,
+// before it is imported. The source is a zip file or a directory, as for Import.
+func (s *ProxiesServiceOp) CheckBundleConflicts(proxyName, source, basepath, env string) ([]BasePathConflict, error) {
+  candidate, e := bundleConnections(source)
+  if e != nil {
+    return nil, e
+  }
+  return checkConflicts(s.client, proxyName, candidate, basepath, env)
+}
+
 // Delete an API Proxy and all its revisions from an organization. This method
 // will fail if any of the revisions of the named API Proxy are currently deployed
 // in any environment.
@@ -97,3 +151,103 @@ func (s *ProxiesServiceOp) Delete(proxyName string) (*DeletedItemInfo, *Response
 func (s *ProxiesServiceOp) GetDeployments(proxyName string) (*Deployment, *Response, error) {
 	return s.deployable.GetDeployments(s.client, uriPathElement, proxyName)
 }
+
+// PruneRevisions deletes old revisions of an API Proxy. It keeps the newest
+// opts.KeepLatest revisions, and every revision currently deployed in any
+// environment. With opts.DryRun, it reports what it would delete without deleting.
+func (s *ProxiesServiceOp) PruneRevisions(proxyName string, opts PruneOptions) (*PruneReport, error) {
+	return s.deployable.PruneRevisions(s.client, uriPathElement, proxyName, opts)
+}
+
+// PruneAll applies PruneRevisions to every API Proxy in the organization,
+// returning one report per API Proxy.
+func (s *ProxiesServiceOp) PruneAll(opts PruneOptions) ([]PruneReport, error) {
+	return s.deployable.PruneAll(s.client, uriPathElement, opts)
+}
+
+// Promote deploys into toEnv the revision of an API Proxy that is currently
+// deployed in fromEnv, waits for the deployment to complete on all servers, and
+// then undeploys the revision previously deployed in toEnv. The basepath of the
+// existing deployment in toEnv is preserved. If the new deployment fails, the
+// previous revision is redeployed.
+func (s *ProxiesServiceOp) Promote(proxyName, fromEnv, toEnv string) (*PromoteResult, error) {
+	return s.deployable.Promote(s.client, uriPathElement, proxyName, fromEnv, toEnv)
+}
+
+// Rollback redeploys into env the revision of an API Proxy that was deployed
+// before the current one, with override, waits for it to be deployed on all
+// servers, and undeploys the current revision. The previous revision comes from
+// the deployments made through this client, if any, else from another revision
+// still deployed in env. If there is neither, Rollback returns an error; use
+// RollbackTo to name the revision.
+func (s *ProxiesServiceOp) Rollback(proxyName, env string) (*RollbackResult, error) {
+	return s.deployable.Rollback(s.client, uriPathElement, proxyName, env)
+}
+
+// RollbackTo is like Rollback, but redeploys the named revision.
+func (s *ProxiesServiceOp) RollbackTo(proxyName, env string, rev Revision) (*RollbackResult, error) {
+	return s.deployable.RollbackTo(s.client, uriPathElement, proxyName, env, rev)
+}
+
+// DeploymentHistory returns the revisions of an API Proxy deployed into env
+// through this client, oldest first.
+func (s *ProxiesServiceOp) DeploymentHistory(proxyName, env string) []Revision {
+	return s.deployable.DeploymentHistory(s.client, uriPathElement, proxyName, env)
+}
+
+// GetRevision retrieves the information about a specific revision of an API Proxy,
+// including the names of its policies, resources, and endpoints.
+func (s *ProxiesServiceOp) GetRevision(proxyName string, rev Revision) (*DeployableRevision, *Response, error) {
+	return s.deployable.GetRevision(s.client, uriPathElement, proxyName, rev)
+}
+
+// ListPolicies retrieves the names of the policies in a revision of an API Proxy.
+func (s *ProxiesServiceOp) ListPolicies(proxyName string, rev Revision) ([]string, *Response, error) {
+	return s.deployable.ListPolicies(s.client, uriPathElement, proxyName, rev)
+}
+
+// GetPolicy retrieves the XML configuration of a policy in a revision of an API Proxy.
+func (s *ProxiesServiceOp) GetPolicy(proxyName string, rev Revision, policyName string) (string, *Response, error) {
+	return s.deployable.GetPolicy(s.client, uriPathElement, proxyName, rev, policyName)
+}
+
+// ListResourceFiles retrieves the names and types of the resource files in a
+// revision of an API Proxy.
+func (s *ProxiesServiceOp) ListResourceFiles(proxyName string, rev Revision) ([]ResourceFile, *Response, error) {
+	return s.deployable.ListResourceFiles(s.client, uriPathElement, proxyName, rev)
+}
+
+// GetResourceFile retrieves the content of a resource file in a revision of an API Proxy.
+// The resourceType is one of jsc, java, py, node, xsl, wsdl, xsd, and so on.
+func (s *ProxiesServiceOp) GetResourceFile(proxyName string, rev Revision, resourceType, resourceName string) ([]byte, *Response, error) {
+	return s.deployable.GetResourceFile(s.client, uriPathElement, proxyName, rev, resourceType, resourceName)
+}
+
+// CreateResourceFile adds a resource file to a revision of an API Proxy.
+func (s *ProxiesServiceOp) CreateResourceFile(proxyName string, rev Revision, resourceType, resourceName string, content io.Reader) (*ResourceFile, *Response, error) {
+	return s.deployable.CreateResourceFile(s.client, uriPathElement, proxyName, rev, resourceType, resourceName, content)
+}
+
+// UpdateResourceFile replaces the content of a resource file in a revision of
+// an API Proxy. This allows a small fix, like a change to one JavaScript file,
+// without exporting and re-importing the bundle.
+func (s *ProxiesServiceOp) UpdateResourceFile(proxyName string, rev Revision, resourceType, resourceName string, content io.Reader) (*ResourceFile, *Response, error) {
+	return s.deployable.UpdateResourceFile(s.client, uriPathElement, proxyName, rev, resourceType, resourceName, content)
+}
+
+// DeleteResourceFile removes a resource file from a revision of an API Proxy.
+func (s *ProxiesServiceOp) DeleteResourceFile(proxyName string, rev Revision, resourceType, resourceName string) (*ResourceFile, *Response, error) {
+	return s.deployable.DeleteResourceFile(s.client, uriPathElement, proxyName, rev, resourceType, resourceName)
+}
+
+// GetProxyEndpoint retrieves the XML definition of a ProxyEndpoint in a revision
+// of an API Proxy.
+func (s *ProxiesServiceOp) GetProxyEndpoint(proxyName string, rev Revision, endpointName string) (string, *Response, error) {
+	return s.deployable.GetProxyEndpoint(s.client, uriPathElement, proxyName, rev, endpointName)
+}
+
+// GetTargetEndpoint retrieves the XML definition of a TargetEndpoint in a revision
+// of an API Proxy.
+func (s *ProxiesServiceOp) GetTargetEndpoint(proxyName string, rev Revision, endpointName string) (string, *Response, error) {
+	return s.deployable.GetTargetEndpoint(s.client, uriPathElement, proxyName, rev, endpointName)
+}
@@ -0,0 +1,186 @@
+package apigee
+
+import (
+  "fmt"
+  "sort"
+  "sync"
+)
+
+const defaultPruneConcurrency = 4
+
+// PruneOptions holds the settings for pruning old revisions of API Proxies or
+// SharedFlows.
+type PruneOptions struct {
+  // The number of newest revisions to keep. Values less than 1 are treated as
+  // 1; the latest revision is never deleted.
+  KeepLatest int
+
+  // Optional. If true, report what would be deleted, without deleting anything.
+  DryRun bool
+
+  // Optional. The maximum number of concurrent calls to the Edge Admin API.
+  // Defaults to 4.
+  MaxConcurrent int
+}
+
+// PruneReport describes the result of pruning the revisions of one API Proxy
+// or SharedFlow.
+type PruneReport struct {
+  Name     string
+  DryRun   bool
+  // revisions kept because they are among the newest
+  Kept     []Revision
+  // revisions kept because they are deployed in at least one environment
+  Deployed []Revision
+  // revisions deleted, or that would be deleted in a dry run
+  Deleted  []Revision
+  // revisions that could not be deleted, with the reason
+  Failed   map[Revision]error
+  // non-nil if pruning did not complete for this asset
+  Error    error
+}
+
+// revisionsToPrune partitions the revisions into those to keep because they
+// are among the newest keepLatest, those to keep because they are deployed,
+// and those to delete. Each returned slice is sorted newest first.
+func revisionsToPrune(revisions []Revision, deployed map[Revision]bool, keepLatest int) (kept, keptDeployed, toDelete []Revision) {
+  if keepLatest < 1 {
+    keepLatest = 1
+  }
+  sorted := append([]Revision{}, revisions...)
+  sort.Slice(sorted, func(i, j int) bool { return sorted[i] > sorted[j] })
+  for i, rev := range sorted {
+    switch {
+      case i < keepLatest:
+        kept = append(kept, rev)
+      case deployed[rev]:
+        keptDeployed = append(keptDeployed, rev)
+      default:
+        toDelete = append(toDelete, rev)
+    }
+  }
+  return kept, keptDeployed, toDelete
+}
+
+// deployedRevisions returns the set of revisions deployed in any environment.
+func deployedRevisions(deployment *Deployment) map[Revision]bool {
+  deployed := map[Revision]bool{}
+  for _, env := range deployment.Environments {
+    for _, rev := range env.Revision {
+      deployed[rev.Number] = true
+    }
+  }
+  return deployed
+}
+
+func (s *Deployable) prune(client *ApigeeClient, uriPathElement, assetName string, opts PruneOptions, limiter chan struct{}) (*PruneReport, error) {
+  report := &PruneReport{Name: assetName, DryRun: opts.DryRun, Failed: map[Revision]error{}}
+
+  limiter <- struct{}{}
+  asset, _, e := s.Get(client, uriPathElement, assetName)
+  <-limiter
+  if e != nil {
+    report.Error = e
+    return report, e
+  }
+  limiter <- struct{}{}
+  deployment, _, e := s.GetDeployments(client, uriPathElement, assetName)
+  <-limiter
+  if e != nil {
+    report.Error = e
+    return report, e
+  }
+
+  var toDelete []Revision
+  report.Kept, report.Deployed, toDelete = revisionsToPrune(asset.Revisions, deployedRevisions(deployment), opts.KeepLatest)
+  if opts.DryRun {
+    report.Deleted = toDelete
+    return report, nil
+  }
+
+  var wg sync.WaitGroup
+  var mu sync.Mutex
+  for _, rev := range toDelete {
+    // take the slot before starting the goroutine, so that at most one
+    // goroutine per slot is running
+    limiter <- struct{}{}
+    wg.Add(1)
+    go func(rev Revision) {
+      defer wg.Done()
+      _, _, e := s.DeleteRevision(client, uriPathElement, assetName, rev)
+      <-limiter
+      mu.Lock()
+      defer mu.Unlock()
+      if e != nil {
+        report.Failed[rev] = e
+      } else {
+        report.Deleted = append(report.Deleted, rev)
+      }
+    }(rev)
+  }
+  wg.Wait()
+  sort.Slice(report.Deleted, func(i, j int) bool { return report.Deleted[i] > report.Deleted[j] })
+
+  if len(report.Failed) > 0 {
+    report.Error = fmt.Errorf("could not delete %d of %d revisions of %s", len(report.Failed), len(toDelete), assetName)
+    return report, report.Error
+  }
+  return report, nil
+}
+
+func newPruneLimiter(opts PruneOptions) chan struct{} {
+  n := opts.MaxConcurrent
+  if n < 1 {
+    n = defaultPruneConcurrency
+  }
+  return make(chan struct{}, n)
+}
+
+// PruneRevisions deletes the revisions of an API Proxy or SharedFlow, except the
+// newest opts.KeepLatest, and any revision currently deployed in any environment.
+func (s *Deployable) PruneRevisions(client *ApigeeClient, uriPathElement, assetName string, opts PruneOptions) (*PruneReport, error) {
+  return s.prune(client, uriPathElement, assetName, opts, newPruneLimiter(opts))
+}
+
+// PruneAll applies PruneRevisions to every API Proxy or SharedFlow in the
+// organization. It returns one report per asset. The returned error is non-nil
+// if pruning failed for any asset; the reports show which.
+func (s *Deployable) PruneAll(client *ApigeeClient, uriPathElement string, opts PruneOptions) ([]PruneReport, error) {
+  names, _, e := s.List(client, uriPathElement)
+  if e != nil {
+    return nil, e
+  }
+  limiter := newPruneLimiter(opts)
+  reports := make([]PruneReport, len(names))
+  // one worker per slot of the limiter prunes the assets in turn; a worker
+  // holds no slot while it waits for the next asset, so the workers and their
+  // requests cannot starve each other
+  indexes := make(chan int)
+  var wg sync.WaitGroup
+  for w := 0; w < cap(limiter); w++ {
+    wg.Add(1)
+    go func() {
+      defer wg.Done()
+      for i := range indexes {
+        report, _ := s.prune(client, uriPathElement, names[i], opts, limiter)
+        reports[i] = *report
+      }
+    }()
+  }
+  for i := range names {
+    indexes <- i
+  }
+  close(indexes)
+  wg.Wait()
+
+  failed := 0
+  for _, report := range reports {
+    if report.Error != nil {
+      failed++
+    }
+  }
+  if failed > 0 {
+    return reports, fmt.Errorf("pruning failed for %d of %d assets in %s", failed, len(names), uriPathElement)
+  }
+  return reports, nil
+}
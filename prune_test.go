@@ -0,0 +1,151 @@
+package apigee
+
+import (
+  "fmt"
+  "net/http"
+  "sort"
+  "strings"
+  "sync"
+  "testing"
+  "time"
+)
+
+func TestRevisionsToPrune(t *testing.T) {
+  testCases := []struct {
+    desc         string
+    revisions    []Revision
+    deployed     map[Revision]bool
+    keepLatest   int
+    kept         string
+    keptDeployed string
+    toDelete     string
+  }{
+    {"keep newest 2", []Revision{1, 2, 3, 4, 5}, map[Revision]bool{}, 2, "[5 4]", "[]", "[3 2 1]"},
+    {"deployed survive", []Revision{3, 1, 5, 2, 4}, map[Revision]bool{1: true, 3: true}, 2, "[5 4]", "[3 1]", "[2]"},
+    {"keep at least 1", []Revision{1, 2, 3}, map[Revision]bool{}, 0, "[3]", "[]", "[2 1]"},
+    {"keep more than exist", []Revision{1, 2}, map[Revision]bool{}, 5, "[2 1]", "[]", "[]"},
+    {"no revisions", []Revision{}, map[Revision]bool{}, 3, "[]", "[]", "[]"},
+  }
+  for _, tc := range testCases {
+    kept, keptDeployed, toDelete := revisionsToPrune(tc.revisions, tc.deployed, tc.keepLatest)
+    got := fmt.Sprintf("%v %v %v", kept, keptDeployed, toDelete)
+    expected := fmt.Sprintf("%s %s %s", tc.kept, tc.keptDeployed, tc.toDelete)
+    if got != expected {
+      t.Errorf("%s: got=%s, expected=%s", tc.desc, got, expected)
+    }
+  }
+}
+
+func TestDeployedRevisions(t *testing.T) {
+  deployment := &Deployment{
+    Environments: []EnvironmentDeployment{
+      {Name: "test", Revision: []RevisionDeployment{{Number: 7}, {Number: 8}}},
+      {Name: "prod", Revision: []RevisionDeployment{{Number: 5}}},
+    },
+  }
+  deployed := deployedRevisions(deployment)
+  for _, rev := range []Revision{5, 7, 8} {
+    if !deployed[rev] {
+      t.Errorf("revision %d: expected deployed", rev)
+    }
+  }
+  if deployed[6] {
+    t.Errorf("revision 6: expected not deployed")
+  }
+}
+
+// pruneStub stands in for Edge for a set of API Proxies, each with revisions 1
+// to 5, of which revision 2 is deployed. It records the deletes, and the most
+// requests it saw in flight at once.
+type pruneStub struct {
+  mu        sync.Mutex
+  names     []string
+  deleted   []string
+  inFlight  int
+  maxFlight int
+}
+
+func (d *pruneStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+  d.mu.Lock()
+  d.inFlight++
+  if d.inFlight > d.maxFlight {
+    d.maxFlight = d.inFlight
+  }
+  d.mu.Unlock()
+  time.Sleep(2 * time.Millisecond)
+  defer func() {
+    d.mu.Lock()
+    d.inFlight--
+    d.mu.Unlock()
+  }()
+
+  parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/o/org1/apis"), "/")
+  switch {
+  case r.Method == "GET" && len(parts) == 1:
+    fmt.Fprintf(w, `["%s"]`, strings.Join(d.names, `","`))
+  case r.Method == "GET" && len(parts) == 2:
+    fmt.Fprintf(w, `{"name":%q,"revision":["1","2","3","4","5"]}`, parts[1])
+  case r.Method == "GET" && len(parts) == 3 && parts[2] == "deployments":
+    fmt.Fprintf(w, `{"name":%q,"environment":[{"name":"prod","revision":[{"name":"2","state":"deployed"}]}]}`, parts[1])
+  case r.Method == "DELETE" && len(parts) == 4 && parts[2] == "revisions":
+    d.mu.Lock()
+    d.deleted = append(d.deleted, parts[1]+"/"+parts[3])
+    d.mu.Unlock()
+    fmt.Fprintf(w, `{"name":%q,"revision":%q}`, parts[1], parts[3])
+  default:
+    http.Error(w, `{"code":"unexpected"}`, http.StatusBadRequest)
+  }
+}
+
+func TestPruneRevisions(t *testing.T) {
+  stub := &pruneStub{names: []string{"flights"}}
+  client, stop := newStubClient(t, stub.ServeHTTP)
+  defer stop()
+  report, e := client.Proxies.PruneRevisions("flights", PruneOptions{KeepLatest: 2})
+  if e != nil {
+    t.Errorf("unexpected error: %v", e)
+    return
+  }
+  got := fmt.Sprintf("%v %v %v", report.Kept, report.Deployed, report.Deleted)
+  if got != "[5 4] [2] [3 1]" {
+    t.Errorf("report got=%s, expected=[5 4] [2] [3 1]", got)
+  }
+  sort.Strings(stub.deleted)
+  if fmt.Sprintf("%v", stub.deleted) != "[flights/1 flights/3]" {
+    t.Errorf("deleted got=%v", stub.deleted)
+  }
+}
+
+func TestPruneAll(t *testing.T) {
+  stub := &pruneStub{}
+  for i := 0; i < 12; i++ {
+    stub.names = append(stub.names, fmt.Sprintf("proxy%02d", i))
+  }
+  client, stop := newStubClient(t, stub.ServeHTTP)
+  defer stop()
+  reports, e := client.Proxies.PruneAll(PruneOptions{KeepLatest: 2, MaxConcurrent: 3})
+  if e != nil {
+    t.Errorf("unexpected error: %v", e)
+    return
+  }
+  if len(reports) != len(stub.names) {
+    t.Errorf("got %d reports, expected %d", len(reports), len(stub.names))
+  }
+  for i, report := range reports {
+    got := fmt.Sprintf("%s %v %v %v", report.Name, report.Kept, report.Deployed, report.Deleted)
+    if expected := stub.names[i] + " [5 4] [2] [3 1]"; got != expected {
+      t.Errorf("report got=%s, expected=%s", got, expected)
+    }
+  }
+  if len(stub.deleted) != 2*len(stub.names) {
+    t.Errorf("deleted got=%v", stub.deleted)
+  }
+  for _, deleted := range stub.deleted {
+    if !strings.HasSuffix(deleted, "/1") && !strings.HasSuffix(deleted, "/3") {
+      t.Errorf("deleted a kept revision: %s", deleted)
+    }
+  }
+  if stub.maxFlight > 3 {
+    t.Errorf("requests in flight got=%d, expected at most 3", stub.maxFlight)
+  }
+}
@@ -0,0 +1,268 @@
+package apigee
+
+import (
+  "path"
+  "errors"
+  "fmt"
+  "strings"
+)
+
+const (
+  referencesPath = "references"
+  referencePrefix = "ref://"
+)
+
+// ReferencesService is an interface for interfacing with the Apigee Edge Admin API
+// dealing with references in an environment. A reference lets a virtual host or
+// TargetServer name a keystore indirectly, as "ref://name", so that a keystore
+// can be replaced without changing its users.
+type ReferencesService interface {
+  List(string) ([]string, *Response, error)
+  Get(string, string) (*Reference, *Response, error)
+  Create(Reference, string) (*Reference, *Response, error)
+  Update(Reference, string) (*Reference, *Response, error)
+  Delete(string, string) (*Reference, *Response, error)
+  Rotate(string, string, AliasUpload, string) (*RotationResult, error)
+}
+
+type ReferencesServiceOp struct {
+  client *ApigeeClient
+}
+
+var _ ReferencesService = &ReferencesServiceOp{}
+
+// Reference contains information about a reference within an Edge environment.
+type Reference struct {
+  Name          string   `json:"name,omitempty"`
+  Refers        string   `json:"refers,omitempty"`
+  ResourceType  string   `json:"resourceType,omitempty"`
+}
+
+// ReferenceUser identifies a virtual host or TargetServer that uses a reference,
+// and whether the reference resolves for it.
+type ReferenceUser struct {
+  // "virtualhost" or "targetserver"
+  Kind      string
+  Name      string
+  KeyAlias  string
+  // empty if the reference resolves to a keystore holding the needed alias
+  Problem   string
+}
+
+// RotationResult describes the outcome of rotating the keystore behind a reference.
+type RotationResult struct {
+  Reference         string
+  Env               string
+  PreviousKeystore  string
+  Keystore          string
+  Users             []ReferenceUser
+}
+
+func referencePath(env string, elements ...string) string {
+  return path.Join(append([]string{"e", env, referencesPath}, elements...)...)
+}
+
+// List retrieves the names of the references in an environment.
+func (s *ReferencesServiceOp) List(env string) ([]string, *Response, error) {
+  req, e := s.client.NewRequest("GET", referencePath(env), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  namelist := make([]string,0)
+  resp, e := s.client.Do(req, &namelist)
+  if e != nil {
+    return nil, resp, e
+  }
+  return namelist, resp, e
+}
+
+// Get retrieves a reference, including the name of the resource it refers to.
+func (s *ReferencesServiceOp) Get(name, env string) (*Reference, *Response, error) {
+  req, e := s.client.NewRequest("GET", referencePath(env, name), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedRef := Reference{}
+  resp, e := s.client.Do(req, &returnedRef)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedRef, resp, e
+}
+
+// Create adds a reference to an environment. If ResourceType is empty, it
+// defaults to KeyStore.
+func (s *ReferencesServiceOp) Create(ref Reference, env string) (*Reference, *Response, error) {
+  if ref.Name == "" || ref.Refers == "" {
+    return nil, nil, errors.New("must specify the Name and Refers of the reference to create")
+  }
+  if ref.ResourceType == "" {
+    ref.ResourceType = "KeyStore"
+  }
+  req, e := s.client.NewRequest("POST", referencePath(env), ref)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedRef := Reference{}
+  resp, e := s.client.Do(req, &returnedRef)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedRef, resp, e
+}
+
+// Update changes the resource that a reference refers to.
+func (s *ReferencesServiceOp) Update(ref Reference, env string) (*Reference, *Response, error) {
+  if ref.Name == "" || ref.Refers == "" {
+    return nil, nil, errors.New("must specify the Name and Refers of the reference to update")
+  }
+  if ref.ResourceType == "" {
+    ref.ResourceType = "KeyStore"
+  }
+  req, e := s.client.NewRequest("PUT", referencePath(env, ref.Name), ref)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedRef := Reference{}
+  resp, e := s.client.Do(req, &returnedRef)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedRef, resp, e
+}
+
+// Delete removes a reference from an environment.
+func (s *ReferencesServiceOp) Delete(name, env string) (*Reference, *Response, error) {
+  req, e := s.client.NewRequest("DELETE", referencePath(env, name), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  deletedRef := Reference{}
+  resp, e := s.client.Do(req, &deletedRef)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &deletedRef, resp, e
+}
+
+// usesReference returns the key alias the SSLInfo needs from the reference, and
+// true, if either its keystore or its truststore is the named reference.
+func usesReference(info *SSLInfo, refName string) (string, bool) {
+  if info == nil {
+    return "", false
+  }
+  ref := referencePrefix + refName
+  if info.KeyStore == ref {
+    return info.KeyAlias, true
+  }
+  if info.TrustStore == ref {
+    return "", true
+  }
+  return "", false
+}
+
+// resolveProblem checks that the keystore holds the alias, if one is needed,
+// returning a description of the problem, or the empty string.
+func resolveProblem(keystore *Keystore, alias string) string {
+  if keystore == nil {
+    return "the keystore does not exist"
+  }
+  if alias == "" {
+    return ""
+  }
+  for _, entry := range keystore.Aliases {
+    if entry.AliasName == alias {
+      return ""
+    }
+  }
+  return fmt.Sprintf("keystore %s has no alias %s", keystore.Name, alias)
+}
+
+// Rotate uploads a key and certificate into a new keystore, verifies that the
+// new keystore satisfies every virtual host and TargetServer in the environment
+// that uses the reference, and only then repoints the reference at it. If any
+// user would not resolve, the reference is left pointing at the previous
+// keystore and the returned error lists the failures; the Users in the result
+// show which. The new keystore is not removed in that case.
+func (s *ReferencesServiceOp) Rotate(refName, newKeystore string, upload AliasUpload, env string) (*RotationResult, error) {
+  ref, _, e := s.Get(refName, env)
+  if e != nil {
+    return nil, e
+  }
+  result := &RotationResult{Reference: refName, Env: env, PreviousKeystore: ref.Refers, Keystore: newKeystore}
+
+  _, _, e = s.client.Keystores.Create(newKeystore, env)
+  if e != nil {
+    return result, e
+  }
+  _, _, e = s.client.Keystores.CreateAlias(newKeystore, upload, env)
+  if e != nil {
+    return result, e
+  }
+  keystore, _, e := s.client.Keystores.Get(newKeystore, env)
+  if e != nil && !isNotFound(e) {
+    return result, e
+  }
+
+  result.Users, e = s.referenceUsers(refName, keystore, env)
+  if e != nil {
+    return result, e
+  }
+  if e = unresolvedUsers(refName, result.Users); e != nil {
+    return result, e
+  }
+
+  ref.Refers = newKeystore
+  _, _, e = s.Update(*ref, env)
+  if e != nil {
+    return result, e
+  }
+  return result, nil
+}
+
+// referenceUsers finds the virtual hosts and TargetServers in env that use the
+// named reference, and checks each against the given keystore.
+func (s *ReferencesServiceOp) referenceUsers(refName string, keystore *Keystore, env string) ([]ReferenceUser, error) {
+  users := []ReferenceUser{}
+  vhosts, _, e := s.client.VirtualHosts.List(env)
+  if e != nil {
+    return users, e
+  }
+  for _, name := range vhosts {
+    vhost, _, e := s.client.VirtualHosts.Get(name, env)
+    if e != nil {
+      return users, e
+    }
+    if alias, ok := usesReference(vhost.SSLInfo, refName); ok {
+      users = append(users, ReferenceUser{Kind: "virtualhost", Name: name, KeyAlias: alias, Problem: resolveProblem(keystore, alias)})
+    }
+  }
+  servers, _, e := s.client.TargetServers.List(env)
+  if e != nil {
+    return users, e
+  }
+  for _, name := range servers {
+    server, _, e := s.client.TargetServers.Get(name, env)
+    if e != nil {
+      return users, e
+    }
+    if alias, ok := usesReference(server.SSLInfo, refName); ok {
+      users = append(users, ReferenceUser{Kind: "targetserver", Name: name, KeyAlias: alias, Problem: resolveProblem(keystore, alias)})
+    }
+  }
+  return users, nil
+}
+
+// unresolvedUsers returns an error naming every user with a Problem, or nil.
+func unresolvedUsers(refName string, users []ReferenceUser) error {
+  problems := []string{}
+  for _, user := range users {
+    if user.Problem != "" {
+      problems = append(problems, fmt.Sprintf("%s %s: %s", user.Kind, user.Name, user.Problem))
+    }
+  }
+  if len(problems) > 0 {
+    return fmt.Errorf("reference %s would not resolve for: %s", refName, strings.Join(problems, "; "))
+  }
+  return nil
+}
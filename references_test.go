@@ -0,0 +1,64 @@
+package apigee
+
+import (
+  "strings"
+  "testing"
+)
+
+func TestReference_Users(t *testing.T) {
+  keystore := &Keystore{Name: "ks-2026", Aliases: []KeystoreEntry{{AliasName: "api-cert"}}}
+  testCases := []struct {
+    desc     string
+    info     *SSLInfo
+    uses     bool
+    problem  bool
+  }{
+    {"keystore ref with alias", &SSLInfo{Enabled: true, KeyStore: "ref://api-ref", KeyAlias: "api-cert"}, true, false},
+    {"keystore ref missing alias", &SSLInfo{Enabled: true, KeyStore: "ref://api-ref", KeyAlias: "old-cert"}, true, true},
+    {"truststore ref", &SSLInfo{Enabled: true, TrustStore: "ref://api-ref"}, true, false},
+    {"other ref", &SSLInfo{Enabled: true, KeyStore: "ref://other-ref", KeyAlias: "api-cert"}, false, false},
+    {"direct keystore", &SSLInfo{Enabled: true, KeyStore: "api-ref", KeyAlias: "api-cert"}, false, false},
+    {"no TLS", nil, false, false},
+  }
+  for _, tc := range testCases {
+    alias, uses := usesReference(tc.info, "api-ref")
+    if uses != tc.uses {
+      t.Errorf("%s: uses[got=%v, expected=%v]", tc.desc, uses, tc.uses)
+      continue
+    }
+    if !uses {
+      continue
+    }
+    if problem := resolveProblem(keystore, alias); (problem != "") != tc.problem {
+      t.Errorf("%s: problem[got=%q, expected=%v]", tc.desc, problem, tc.problem)
+    }
+  }
+  if problem := resolveProblem(nil, ""); problem == "" {
+    t.Errorf("missing keystore: expected a problem")
+  }
+}
+
+func TestUnresolvedUsers(t *testing.T) {
+  testCases := []struct {
+    desc     string
+    users    []ReferenceUser
+    wantErr  bool
+  }{
+    {"no users", nil, false},
+    {"all resolve", []ReferenceUser{{Kind: "virtualhost", Name: "secure", KeyAlias: "api-cert"}}, false},
+    {"one fails", []ReferenceUser{
+      {Kind: "virtualhost", Name: "secure", KeyAlias: "api-cert"},
+      {Kind: "targetserver", Name: "backend", KeyAlias: "old-cert", Problem: "keystore ks-2026 has no alias old-cert"},
+    }, true},
+  }
+  for _, tc := range testCases {
+    e := unresolvedUsers("api-ref", tc.users)
+    if (e != nil) != tc.wantErr {
+      t.Errorf("%s: err[got=%v, expected=%v]", tc.desc, e, tc.wantErr)
+      continue
+    }
+    if e != nil && !strings.Contains(e.Error(), "targetserver backend") {
+      t.Errorf("%s: error does not name the failing user: %v", tc.desc, e)
+    }
+  }
+}
@@ -0,0 +1,325 @@
+package apigee
+
+import (
+  "bytes"
+  "errors"
+  "fmt"
+  "io"
+  "io/ioutil"
+  "net/url"
+  "os"
+  "path"
+  "path/filepath"
+  "sort"
+)
+
+const resourceFilesPath = "resourcefiles"
+
+// ResourceTypes lists the types of resource files that Edge accepts. Each is
+// also the name of the directory that holds files of that type, in a bundle or
+// in a directory passed to Sync.
+var ResourceTypes = []string{"jsc", "java", "py", "node", "xsl", "wsdl", "xsd"}
+
+// ResourceFilesService is an interface for interfacing with the Apigee Edge Admin API
+// dealing with resource files, like JavaScript or XSL files, that are shared by
+// all the API Proxies in an organization or an environment. The service attached
+// to the ApigeeClient manages the files scoped to the organization; use
+// Environment to get a service that manages the files scoped to an environment.
+type ResourceFilesService interface {
+  Environment(string) ResourceFilesService
+  List(string) ([]ResourceFile, *Response, error)
+  Get(string, string) ([]byte, *Response, error)
+  Create(string, string, io.Reader) (*ResourceFile, *Response, error)
+  CreateFromFile(string, string) (*ResourceFile, *Response, error)
+  Update(string, string, io.Reader) (*ResourceFile, *Response, error)
+  Delete(string, string) (*ResourceFile, *Response, error)
+  Sync(string, bool) (*ResourceSyncReport, error)
+}
+
+type ResourceFilesServiceOp struct {
+  client *ApigeeClient
+  // the path prefix for the scope; empty for the organization
+  scope string
+}
+
+var _ ResourceFilesService = &ResourceFilesServiceOp{}
+
+// ResourceSyncReport describes the changes Sync made, or in a dry run would
+// make, to the resource files in a scope.
+type ResourceSyncReport struct {
+  Created    []ResourceFile
+  Updated    []ResourceFile
+  Deleted    []ResourceFile
+  Unchanged  []ResourceFile
+}
+
+func isResourceType(resourceType string) bool {
+  for _, t := range ResourceTypes {
+    if t == resourceType {
+      return true
+    }
+  }
+  return false
+}
+
+func checkResourceType(resourceType string) error {
+  if !isResourceType(resourceType) {
+    return fmt.Errorf("unsupported resource type %q; must be one of %v", resourceType, ResourceTypes)
+  }
+  return nil
+}
+
+// Environment returns a ResourceFilesService that manages the resource files
+// scoped to the named environment.
+func (s *ResourceFilesServiceOp) Environment(env string) ResourceFilesService {
+  return &ResourceFilesServiceOp{client: s.client, scope: path.Join("e", env)}
+}
+
+func (s *ResourceFilesServiceOp) resourcePath(elements ...string) string {
+  return path.Join(append([]string{s.scope, resourceFilesPath}, elements...)...)
+}
+
+// List retrieves the resource files in the scope. Pass an empty resourceType to
+// list the files of all types.
+func (s *ResourceFilesServiceOp) List(resourceType string) ([]ResourceFile, *Response, error) {
+  p := s.resourcePath()
+  if resourceType != "" {
+    if e := checkResourceType(resourceType); e != nil {
+      return nil, nil, e
+    }
+    p = s.resourcePath(resourceType)
+  }
+  req, e := s.client.NewRequest("GET", p, nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  root := resourceFilesRoot{}
+  resp, e := s.client.Do(req, &root)
+  if e != nil {
+    return nil, resp, e
+  }
+  return root.ResourceFiles, resp, e
+}
+
+// Get retrieves the content of a resource file.
+func (s *ResourceFilesServiceOp) Get(resourceType, resourceName string) ([]byte, *Response, error) {
+  if e := checkResourceType(resourceType); e != nil {
+    return nil, nil, e
+  }
+  return getRaw(s.client, s.resourcePath(resourceType, resourceName), octetStream)
+}
+
+// Create adds a resource file to the scope, with the content read from the reader.
+func (s *ResourceFilesServiceOp) Create(resourceType, resourceName string, content io.Reader) (*ResourceFile, *Response, error) {
+  if e := checkResourceType(resourceType); e != nil {
+    return nil, nil, e
+  }
+  if resourceName == "" {
+    return nil, nil, errors.New("cannot create a resource file with no name")
+  }
+  // append the query params
+  origURL, e := url.Parse(s.resourcePath())
+  if e != nil {
+    return nil, nil, e
+  }
+  q := origURL.Query()
+  q.Add("type", resourceType)
+  q.Add("name", resourceName)
+  origURL.RawQuery = q.Encode()
+
+  req, e := s.client.NewRequest("POST", origURL.String(), content)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedFile := ResourceFile{}
+  resp, e := s.client.Do(req, &returnedFile)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedFile, resp, e
+}
+
+// CreateFromFile adds a local file to the scope as a resource file of the
+// given type. The resource file takes the base name of the local file.
+func (s *ResourceFilesServiceOp) CreateFromFile(resourceType, filename string) (*ResourceFile, *Response, error) {
+  f, e := os.Open(filename)
+  if e != nil {
+    return nil, nil, e
+  }
+  defer f.Close()
+  return s.Create(resourceType, filepath.Base(filename), f)
+}
+
+// Update replaces the content of an existing resource file.
+func (s *ResourceFilesServiceOp) Update(resourceType, resourceName string, content io.Reader) (*ResourceFile, *Response, error) {
+  if e := checkResourceType(resourceType); e != nil {
+    return nil, nil, e
+  }
+  req, e := s.client.NewRequest("PUT", s.resourcePath(resourceType, resourceName), content)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedFile := ResourceFile{}
+  resp, e := s.client.Do(req, &returnedFile)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedFile, resp, e
+}
+
+// Delete removes a resource file from the scope.
+func (s *ResourceFilesServiceOp) Delete(resourceType, resourceName string) (*ResourceFile, *Response, error) {
+  if e := checkResourceType(resourceType); e != nil {
+    return nil, nil, e
+  }
+  req, e := s.client.NewRequest("DELETE", s.resourcePath(resourceType, resourceName), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  deletedFile := ResourceFile{}
+  resp, e := s.client.Do(req, &deletedFile)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &deletedFile, resp, e
+}
+
+// readResourceDirectory reads the resource files in a local directory, which
+// holds one subdirectory per resource type, like jsc/ or xsl/. Other
+// subdirectories, nested directories, and files rejected by smartFilter are
+// ignored. The result maps each file to its content, and lists the types whose
+// subdirectory exists, even if empty. It is an error if dir is not a directory.
+func readResourceDirectory(dir string) (map[ResourceFile][]byte, map[string]bool, error) {
+  info, e := os.Stat(dir)
+  if e != nil {
+    return nil, nil, e
+  }
+  if !info.IsDir() {
+    return nil, nil, fmt.Errorf("%s is not a directory", dir)
+  }
+  local := map[ResourceFile][]byte{}
+  types := map[string]bool{}
+  for _, resourceType := range ResourceTypes {
+    typeDir := filepath.Join(dir, resourceType)
+    infos, e := ioutil.ReadDir(typeDir)
+    if e != nil {
+      if os.IsNotExist(e) {
+        continue
+      }
+      return nil, nil, e
+    }
+    types[resourceType] = true
+    for _, info := range infos {
+      if info.IsDir() || !smartFilter(info.Name()) {
+        continue
+      }
+      content, e := ioutil.ReadFile(filepath.Join(typeDir, info.Name()))
+      if e != nil {
+        return nil, nil, e
+      }
+      local[ResourceFile{Name: info.Name(), Type: resourceType}] = content
+    }
+  }
+  return local, types, nil
+}
+
+func sortResourceFiles(files []ResourceFile) {
+  sort.Slice(files, func(i, j int) bool {
+    if files[i].Type != files[j].Type {
+      return files[i].Type < files[j].Type
+    }
+    return files[i].Name < files[j].Name
+  })
+}
+
+// planResourceSync compares the local files with the remote ones. It returns
+// the files to create, the files present on both sides whose content must be
+// compared, and the files to delete. Only remote files of the given types,
+// those with a local subdirectory, are considered; the others are never
+// deleted.
+func planResourceSync(local map[ResourceFile][]byte, types map[string]bool, remote []ResourceFile) (create, compare, remove []ResourceFile) {
+  existing := map[ResourceFile]bool{}
+  for _, rf := range remote {
+    if !types[rf.Type] {
+      continue
+    }
+    existing[rf] = true
+    if _, ok := local[rf]; !ok {
+      remove = append(remove, rf)
+    }
+  }
+  for rf := range local {
+    if existing[rf] {
+      compare = append(compare, rf)
+    } else {
+      create = append(create, rf)
+    }
+  }
+  sortResourceFiles(create)
+  sortResourceFiles(compare)
+  sortResourceFiles(remove)
+  return create, compare, remove
+}
+
+// Sync mirrors a local directory into the scope. The directory holds one
+// subdirectory per resource type, like jsc/ or xsl/, the same layout as the
+// resources directory of a bundle. Files missing from the scope are created,
+// files whose content differs are updated, and resource files that are not in
+// the directory are deleted, but only for the types that have a subdirectory;
+// to delete all the files of a type, leave its subdirectory empty. Creates and
+// updates all run before any delete. With dryRun, nothing is changed and the report
+// lists the planned changes; otherwise it lists what was done, up to any error.
+func (s *ResourceFilesServiceOp) Sync(dir string, dryRun bool) (*ResourceSyncReport, error) {
+  local, types, e := readResourceDirectory(dir)
+  if e != nil {
+    return nil, e
+  }
+  remote, _, e := s.List("")
+  if e != nil {
+    return nil, e
+  }
+  create, compare, remove := planResourceSync(local, types, remote)
+
+  report := &ResourceSyncReport{}
+  changed := []ResourceFile{}
+  for _, rf := range compare {
+    current, _, e := s.Get(rf.Type, rf.Name)
+    if e != nil {
+      return report, e
+    }
+    if bytes.Equal(current, local[rf]) {
+      report.Unchanged = append(report.Unchanged, rf)
+    } else {
+      changed = append(changed, rf)
+    }
+  }
+  if dryRun {
+    report.Created = create
+    report.Updated = changed
+    report.Deleted = remove
+    return report, nil
+  }
+
+  for _, rf := range create {
+    _, _, e := s.Create(rf.Type, rf.Name, bytes.NewReader(local[rf]))
+    if e != nil {
+      return report, e
+    }
+    report.Created = append(report.Created, rf)
+  }
+  for _, rf := range changed {
+    _, _, e := s.Update(rf.Type, rf.Name, bytes.NewReader(local[rf]))
+    if e != nil {
+      return report, e
+    }
+    report.Updated = append(report.Updated, rf)
+  }
+  for _, rf := range remove {
+    _, _, e := s.Delete(rf.Type, rf.Name)
+    if e != nil {
+      return report, e
+    }
+    report.Deleted = append(report.Deleted, rf)
+  }
+  return report, nil
+}
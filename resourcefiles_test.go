@@ -0,0 +1,190 @@
+package apigee
+
+import (
+  "fmt"
+  "io/ioutil"
+  "net/http"
+  "os"
+  "path/filepath"
+  "reflect"
+  "strings"
+  "testing"
+)
+
+func TestResourceFilesPath(t *testing.T) {
+  org := &ResourceFilesServiceOp{}
+  env := org.Environment("test").(*ResourceFilesServiceOp)
+  testCases := []struct {
+    got      string
+    expected string
+  }{
+    {org.resourcePath(), "resourcefiles"},
+    {org.resourcePath("jsc", "util.js"), "resourcefiles/jsc/util.js"},
+    {env.resourcePath(), "e/test/resourcefiles"},
+    {env.resourcePath("xsl", "transform.xsl"), "e/test/resourcefiles/xsl/transform.xsl"},
+  }
+  for i, tc := range testCases {
+    if tc.got != tc.expected {
+      t.Errorf("case %d: got=%q, expected=%q", i, tc.got, tc.expected)
+    }
+  }
+}
+
+func TestCheckResourceType(t *testing.T) {
+  for _, resourceType := range []string{"jsc", "java", "py", "node", "xsl", "wsdl", "xsd"} {
+    if e := checkResourceType(resourceType); e != nil {
+      t.Errorf("%s: unexpected error: %v", resourceType, e)
+    }
+  }
+  for _, resourceType := range []string{"", "js", "JSC", "properties"} {
+    if e := checkResourceType(resourceType); e == nil {
+      t.Errorf("%q: expected an error", resourceType)
+    }
+  }
+}
+
+func TestReadResourceDirectory(t *testing.T) {
+  tempDir, e := ioutil.TempDir("", "go-apigee-test-")
+  if e != nil {
+    t.Fatalf("while creating temp dir, error: %v", e)
+  }
+  defer os.RemoveAll(tempDir)
+  files := map[string]string{
+    "jsc/util.js": "var x = 1;",
+    "jsc/util.js~": "backup",
+    "xsl/transform.xsl": "<xsl:stylesheet/>",
+    "other/readme.txt": "ignored",
+  }
+  for name, content := range files {
+    p := filepath.Join(tempDir, filepath.FromSlash(name))
+    if e := os.MkdirAll(filepath.Dir(p), 0755); e != nil {
+      t.Fatalf("while creating dir, error: %v", e)
+    }
+    if e := ioutil.WriteFile(p, []byte(content), 0644); e != nil {
+      t.Fatalf("while writing file, error: %v", e)
+    }
+  }
+  got, types, e := readResourceDirectory(tempDir)
+  if e != nil {
+    t.Errorf("while reading directory, error: %v", e)
+    return
+  }
+  if !reflect.DeepEqual(types, map[string]bool{"jsc": true, "xsl": true}) {
+    t.Errorf("types: got=%v", types)
+  }
+  if len(got) != 2 {
+    t.Errorf("got=%#v", got)
+    return
+  }
+  if string(got[ResourceFile{"util.js", "jsc"}]) != "var x = 1;" {
+    t.Errorf("jsc: got=%q", got[ResourceFile{"util.js", "jsc"}])
+  }
+  if string(got[ResourceFile{"transform.xsl", "xsl"}]) != "<xsl:stylesheet/>" {
+    t.Errorf("xsl: got=%q", got[ResourceFile{"transform.xsl", "xsl"}])
+  }
+
+  for _, dir := range []string{filepath.Join(tempDir, "no-such-dir"), filepath.Join(tempDir, "jsc", "util.js")} {
+    if _, _, e := readResourceDirectory(dir); e == nil {
+      t.Errorf("%s: expected an error", dir)
+    }
+  }
+}
+
+func TestPlanResourceSync(t *testing.T) {
+  local := map[ResourceFile][]byte{
+    {"util.js", "jsc"}: []byte("var x = 1;"),
+    {"new.js", "jsc"}: []byte("var y = 2;"),
+  }
+  types := map[string]bool{"jsc": true, "xsl": true}
+  remote := []ResourceFile{{"util.js", "jsc"}, {"old.xsl", "xsl"}, {"tool.py", "py"}, {"app.properties", "properties"}}
+  create, compare, remove := planResourceSync(local, types, remote)
+  if !reflect.DeepEqual(create, []ResourceFile{{"new.js", "jsc"}}) {
+    t.Errorf("create: got=%v", create)
+  }
+  if !reflect.DeepEqual(compare, []ResourceFile{{"util.js", "jsc"}}) {
+    t.Errorf("compare: got=%v", compare)
+  }
+  if !reflect.DeepEqual(remove, []ResourceFile{{"old.xsl", "xsl"}}) {
+    t.Errorf("remove: got=%v", remove)
+  }
+}
+
+func TestResourceFilesSync(t *testing.T) {
+  tempDir, e := ioutil.TempDir("", "go-apigee-test-")
+  if e != nil {
+    t.Fatalf("while creating temp dir, error: %v", e)
+  }
+  defer os.RemoveAll(tempDir)
+  files := map[string]string{
+    "jsc/same.js": "same",
+    "jsc/changed.js": "new content",
+    "jsc/new.js": "added",
+  }
+  for name, content := range files {
+    p := filepath.Join(tempDir, filepath.FromSlash(name))
+    if e := os.MkdirAll(filepath.Dir(p), 0755); e != nil {
+      t.Fatalf("while creating dir, error: %v", e)
+    }
+    if e := ioutil.WriteFile(p, []byte(content), 0644); e != nil {
+      t.Fatalf("while writing file, error: %v", e)
+    }
+  }
+
+  for _, dryRun := range []bool{true, false} {
+    calls := []string{}
+    client, stop := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+      p := strings.TrimPrefix(r.URL.Path, "/v1/o/org1/e/test/resourcefiles")
+      if r.Method != "GET" {
+        calls = append(calls, r.Method+" "+p)
+      }
+      switch {
+      case r.Method == "GET" && p == "":
+        fmt.Fprint(w, `{"resourceFile":[{"name":"same.js","type":"jsc"},{"name":"changed.js","type":"jsc"},{"name":"old.js","type":"jsc"},{"name":"transform.xsl","type":"xsl"},{"name":"app.properties","type":"properties"}]}`)
+      case r.Method == "GET" && p == "/jsc/same.js":
+        fmt.Fprint(w, "same")
+      case r.Method == "GET" && p == "/jsc/changed.js":
+        fmt.Fprint(w, "old content")
+      default:
+        fmt.Fprint(w, `{}`)
+      }
+    })
+    report, e := client.ResourceFiles.Environment("test").Sync(tempDir, dryRun)
+    stop()
+    if e != nil {
+      t.Errorf("dryRun=%v: unexpected error: %v", dryRun, e)
+      continue
+    }
+    expected := &ResourceSyncReport{
+      Created:   []ResourceFile{{"new.js", "jsc"}},
+      Updated:   []ResourceFile{{"changed.js", "jsc"}},
+      Deleted:   []ResourceFile{{"old.js", "jsc"}},
+      Unchanged: []ResourceFile{{"same.js", "jsc"}},
+    }
+    if !reflect.DeepEqual(report, expected) {
+      t.Errorf("dryRun=%v: report got=%+v, expected=%+v", dryRun, report, expected)
+    }
+    expectedCalls := []string{"POST ", "PUT /jsc/changed.js", "DELETE /jsc/old.js"}
+    if dryRun {
+      expectedCalls = []string{}
+    }
+    if !reflect.DeepEqual(calls, expectedCalls) {
+      t.Errorf("dryRun=%v: calls got=%q, expected=%q", dryRun, calls, expectedCalls)
+    }
+  }
+}
+
+func TestResourceFilesSync_MissingDir(t *testing.T) {
+  calls := []string{}
+  client, stop := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+    calls = append(calls, r.Method+" "+r.URL.Path)
+    fmt.Fprint(w, `{"resourceFile":[{"name":"util.js","type":"jsc"},{"name":"transform.xsl","type":"xsl"}]}`)
+  })
+  defer stop()
+  report, e := client.ResourceFiles.Sync(filepath.Join(os.TempDir(), "go-apigee-no-such-dir"), false)
+  if e == nil {
+    t.Errorf("expected an error, got report=%+v", report)
+  }
+  if len(calls) != 0 {
+    t.Errorf("calls: got=%q, expected none", calls)
+  }
+}
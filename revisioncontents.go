@@ -0,0 +1,197 @@
+package apigee
+
+import (
+  "bytes"
+  "encoding/xml"
+  "fmt"
+  "io"
+  "net/url"
+  "path"
+  "strings"
+)
+
+const appXml = "application/xml"
+
+// ResourceFile identifies a resource file, like a JavaScript or XSL file, within
+// a revision of an API Proxy or SharedFlow, or within an environment or organization.
+type ResourceFile struct {
+  Name   string   `json:"name,omitempty"`
+  Type   string   `json:"type,omitempty"`
+}
+
+// This is just a wrapper struct to aid in de-serialization of resource file lists.
+type resourceFilesRoot struct {
+  ResourceFiles []ResourceFile `json:"resourceFile"`
+}
+
+func revisionPath(uriPathElement, assetName string, rev Revision, elements ...string) string {
+  p := path.Join(uriPathElement, assetName, "revisions", fmt.Sprintf("%d",rev))
+  return path.Join(append([]string{p}, elements...)...)
+}
+
+// getRaw retrieves the content at the path, without decoding it.
+func getRaw(client *ApigeeClient, p, accept string) ([]byte, *Response, error) {
+  req, e := client.NewRequest("GET", p, nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  req.Header.Set("Accept", accept)
+  buf := new(bytes.Buffer)
+  resp, e := client.Do(req, buf)
+  if e != nil {
+    return nil, resp, e
+  }
+  return buf.Bytes(), resp, e
+}
+
+func (s *Deployable) ListPolicies(client *ApigeeClient, uriPathElement, assetName string, rev Revision) ([]string, *Response, error) {
+  req, e := client.NewRequest("GET", revisionPath(uriPathElement, assetName, rev, "policies"), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  namelist := make([]string,0)
+  resp, e := client.Do(req, &namelist)
+  if e != nil {
+    return nil, resp, e
+  }
+  return namelist, resp, e
+}
+
+func (s *Deployable) GetPolicy(client *ApigeeClient, uriPathElement, assetName string, rev Revision, policyName string) (string, *Response, error) {
+  content, resp, e := getRaw(client, revisionPath(uriPathElement, assetName, rev, "policies", policyName), appXml)
+  return string(content), resp, e
+}
+
+func (s *Deployable) GetProxyEndpoint(client *ApigeeClient, uriPathElement, assetName string, rev Revision, endpointName string) (string, *Response, error) {
+  content, resp, e := getRaw(client, revisionPath(uriPathElement, assetName, rev, "proxies", endpointName), appXml)
+  return string(content), resp, e
+}
+
+func (s *Deployable) GetTargetEndpoint(client *ApigeeClient, uriPathElement, assetName string, rev Revision, endpointName string) (string, *Response, error) {
+  content, resp, e := getRaw(client, revisionPath(uriPathElement, assetName, rev, "targets", endpointName), appXml)
+  return string(content), resp, e
+}
+
+func (s *Deployable) ListResourceFiles(client *ApigeeClient, uriPathElement, assetName string, rev Revision) ([]ResourceFile, *Response, error) {
+  req, e := client.NewRequest("GET", revisionPath(uriPathElement, assetName, rev, "resourcefiles"), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  root := resourceFilesRoot{}
+  resp, e := client.Do(req, &root)
+  if e != nil {
+    return nil, resp, e
+  }
+  return root.ResourceFiles, resp, e
+}
+
+func (s *Deployable) GetResourceFile(client *ApigeeClient, uriPathElement, assetName string, rev Revision, resourceType, resourceName string) ([]byte, *Response, error) {
+  return getRaw(client, revisionPath(uriPathElement, assetName, rev, "resourcefiles", resourceType, resourceName), octetStream)
+}
+
+func (s *Deployable) CreateResourceFile(client *ApigeeClient, uriPathElement, assetName string, rev Revision, resourceType, resourceName string, content io.Reader) (*ResourceFile, *Response, error) {
+  // append the query params
+  origURL, e := url.Parse(revisionPath(uriPathElement, assetName, rev, "resourcefiles"))
+  if e != nil {
+    return nil, nil, e
+  }
+  q := origURL.Query()
+  q.Add("type", resourceType)
+  q.Add("name", resourceName)
+  origURL.RawQuery = q.Encode()
+
+  req, e := client.NewRequest("POST", origURL.String(), content)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedFile := ResourceFile{}
+  resp, e := client.Do(req, &returnedFile)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedFile, resp, e
+}
+
+func (s *Deployable) UpdateResourceFile(client *ApigeeClient, uriPathElement, assetName string, rev Revision, resourceType, resourceName string, content io.Reader) (*ResourceFile, *Response, error) {
+  req, e := client.NewRequest("PUT", revisionPath(uriPathElement, assetName, rev, "resourcefiles", resourceType, resourceName), content)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedFile := ResourceFile{}
+  resp, e := client.Do(req, &returnedFile)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedFile, resp, e
+}
+
+func (s *Deployable) DeleteResourceFile(client *ApigeeClient, uriPathElement, assetName string, rev Revision, resourceType, resourceName string) (*ResourceFile, *Response, error) {
+  req, e := client.NewRequest("DELETE", revisionPath(uriPathElement, assetName, rev, "resourcefiles", resourceType, resourceName), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  deletedFile := ResourceFile{}
+  resp, e := client.Do(req, &deletedFile)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &deletedFile, resp, e
+}
+
+// ProxyEndpointConnection holds the HTTPProxyConnection settings of a
+// ProxyEndpoint: the basepath and the virtual hosts it listens on.
+type ProxyEndpointConnection struct {
+  Endpoint      string
+  BasePath      string
+  VirtualHosts  []string
+}
+
+// parseProxyEndpointConnection extracts the HTTPProxyConnection settings from
+// the XML definition of a ProxyEndpoint.
+func parseProxyEndpointConnection(endpointXml string) (*ProxyEndpointConnection, error) {
+  var endpoint struct {
+    Name       string   `xml:"name,attr"`
+    Connection struct {
+      BasePath      string    `xml:"BasePath"`
+      VirtualHosts  []string  `xml:"VirtualHost"`
+    } `xml:"HTTPProxyConnection"`
+  }
+  e := xml.Unmarshal([]byte(endpointXml), &endpoint)
+  if e != nil {
+    return nil, e
+  }
+  connection := &ProxyEndpointConnection{
+    Endpoint: endpoint.Name,
+    BasePath: strings.TrimSpace(endpoint.Connection.BasePath),
+  }
+  for _, vhost := range endpoint.Connection.VirtualHosts {
+    connection.VirtualHosts = append(connection.VirtualHosts, strings.TrimSpace(vhost))
+  }
+  return connection, nil
+}
+
+// proxyConnections retrieves the HTTPProxyConnection settings of each
+// ProxyEndpoint in a revision of an API Proxy.
+func proxyConnections(client *ApigeeClient, proxyName string, rev Revision) ([]ProxyEndpointConnection, error) {
+  d := Deployable{}
+  revision, _, e := d.GetRevision(client, apiUriPathElement, proxyName, rev)
+  if e != nil {
+    return nil, e
+  }
+  connections := []ProxyEndpointConnection{}
+  for _, endpointName := range revision.ProxyEndpoints {
+    endpointXml, _, e := d.GetProxyEndpoint(client, apiUriPathElement, proxyName, rev, endpointName)
+    if e != nil {
+      return nil, e
+    }
+    connection, e := parseProxyEndpointConnection(endpointXml)
+    if e != nil {
+      return nil, e
+    }
+    if connection.Endpoint == "" {
+      connection.Endpoint = endpointName
+    }
+    connections = append(connections, *connection)
+  }
+  return connections, nil
+}
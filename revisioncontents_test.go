@@ -0,0 +1,53 @@
+package apigee
+
+import (
+  "encoding/json"
+  "testing"
+)
+
+const (
+  resourceFilesJson1 = `{
+  "resourceFile" : [ {
+    "name" : "insertResponseHeader.js",
+    "type" : "jsc"
+  }, {
+    "name" : "transform.xsl",
+    "type" : "xsl"
+  } ]
+}`
+)
+
+func TestResourceFiles_Unmarshal(t *testing.T) {
+  var got resourceFilesRoot
+  e := json.Unmarshal([]byte(resourceFilesJson1), &got)
+  if e != nil {
+    t.Errorf("while unmarshaling, error:\n%#v\n", e)
+    return
+  }
+  expected := []ResourceFile{{"insertResponseHeader.js", "jsc"}, {"transform.xsl", "xsl"}}
+  if len(got.ResourceFiles) != len(expected) {
+    t.Errorf("got=%#v, expected=%#v", got.ResourceFiles, expected)
+    return
+  }
+  for i := range expected {
+    if got.ResourceFiles[i] != expected[i] {
+      t.Errorf("%d: got=%#v, expected=%#v", i, got.ResourceFiles[i], expected[i])
+    }
+  }
+}
+
+func TestRevisionPath(t *testing.T) {
+  testCases := []struct {
+    got      string
+    expected string
+  }{
+    {revisionPath("apis", "flights", 3), "apis/flights/revisions/3"},
+    {revisionPath("apis", "flights", 3, "policies", "AM-1"), "apis/flights/revisions/3/policies/AM-1"},
+    {revisionPath("sharedflows", "sf1", 12, "resourcefiles", "jsc", "a.js"), "sharedflows/sf1/revisions/12/resourcefiles/jsc/a.js"},
+  }
+  for _, tc := range testCases {
+    if tc.got != tc.expected {
+      t.Errorf("got=%s, expected=%s", tc.got, tc.expected)
+    }
+  }
+}
@@ -0,0 +1,165 @@
+package apigee
+
+import (
+  "fmt"
+  "path"
+  "sync"
+)
+
+// deploymentHistory records, per asset and environment, the revisions deployed
+// through this client, oldest first.
+type deploymentHistory struct {
+  mu      sync.Mutex
+  entries map[string][]Revision
+}
+
+func newDeploymentHistory() *deploymentHistory {
+  return &deploymentHistory{entries: map[string][]Revision{}}
+}
+
+func historyKey(uriPathElement, assetName, env string) string {
+  return path.Join(uriPathElement, assetName, env)
+}
+
+// record appends the revision to the history, unless it is already the latest entry.
+func (h *deploymentHistory) record(uriPathElement, assetName, env string, rev Revision) {
+  h.mu.Lock()
+  defer h.mu.Unlock()
+  key := historyKey(uriPathElement, assetName, env)
+  revs := h.entries[key]
+  if len(revs) == 0 || revs[len(revs)-1] != rev {
+    h.entries[key] = append(revs, rev)
+  }
+}
+
+// previous returns the most recent entry in the history that differs from the
+// current revision, and the position of that entry.
+func (h *deploymentHistory) previous(uriPathElement, assetName, env string, current Revision) (Revision, int) {
+  h.mu.Lock()
+  defer h.mu.Unlock()
+  revs := h.entries[historyKey(uriPathElement, assetName, env)]
+  for i := len(revs) - 1; i >= 0; i-- {
+    if revs[i] != current {
+      return revs[i], i
+    }
+  }
+  return 0, -1
+}
+
+// truncate discards the history entries after position i, so that a second
+// rollback moves further back rather than returning to the revision just
+// rolled back from.
+func (h *deploymentHistory) truncate(uriPathElement, assetName, env string, i int) {
+  h.mu.Lock()
+  defer h.mu.Unlock()
+  key := historyKey(uriPathElement, assetName, env)
+  if revs := h.entries[key]; i+1 < len(revs) {
+    h.entries[key] = revs[:i+1]
+  }
+}
+
+// list returns the revisions of the asset deployed into the environment
+// through this client, oldest first.
+func (h *deploymentHistory) list(uriPathElement, assetName, env string) []Revision {
+  h.mu.Lock()
+  defer h.mu.Unlock()
+  return append([]Revision{}, h.entries[historyKey(uriPathElement, assetName, env)]...)
+}
+
+// RollbackResult describes the change made by a rollback.
+type RollbackResult struct {
+  Name         string
+  Env          string
+  // the revision that was deployed before the rollback
+  FromRevision Revision
+  // the revision deployed by the rollback
+  ToRevision   Revision
+  BasePath     string
+  // "history" if the previous revision came from the deployments made through
+  // this client, "deployment" if it is another revision still deployed in the
+  // environment, or "caller" if it was named in the call to RollbackTo.
+  Source       string
+}
+
+// previousDeployed returns the newest revision, other than the current one,
+// that is deployed in the environment, or 0 if there is none.
+func previousDeployed(revisions []RevisionDeployment, current Revision) Revision {
+  var previous Revision
+  for _, d := range revisions {
+    if d.State == "deployed" && d.Number != current && d.Number > previous {
+      previous = d.Number
+    }
+  }
+  return previous
+}
+
+// Rollback redeploys into the environment the revision of an API Proxy or
+// SharedFlow that was deployed before the current one, waits for it to be
+// deployed on all servers, and undeploys the current revision. The previous
+// revision comes from the deployments made through this client, or else from
+// another revision still deployed in the environment. If neither names one,
+// Rollback returns an error and changes nothing; use RollbackTo to name the
+// revision.
+func (s *Deployable) Rollback(client *ApigeeClient, uriPathElement, assetName, env string) (*RollbackResult, error) {
+  return s.rollback(client, uriPathElement, assetName, env, 0)
+}
+
+// RollbackTo is like Rollback, but redeploys the named revision.
+func (s *Deployable) RollbackTo(client *ApigeeClient, uriPathElement, assetName, env string, rev Revision) (*RollbackResult, error) {
+  if rev == 0 {
+    return nil, fmt.Errorf("must specify the revision of %s to roll back to", assetName)
+  }
+  return s.rollback(client, uriPathElement, assetName, env, rev)
+}
+
+// rollback implements Rollback, and RollbackTo when target is not 0.
+func (s *Deployable) rollback(client *ApigeeClient, uriPathElement, assetName, env string, target Revision) (*RollbackResult, error) {
+  deployment, _, e := s.GetDeployments(client, uriPathElement, assetName)
+  if e != nil {
+    return nil, e
+  }
+  deployed := deployedInEnv(deployment, env)
+  current := newestDeployed(deployed)
+  if current == nil {
+    return nil, fmt.Errorf("%s is not deployed in %s", assetName, env)
+  }
+
+  result := &RollbackResult{Name: assetName, Env: env, FromRevision: current.Number, ToRevision: target, BasePath: current.BasePath(), Source: "caller"}
+  position := -1
+  if result.ToRevision == 0 && client.history != nil {
+    result.ToRevision, position = client.history.previous(uriPathElement, assetName, env, current.Number)
+    result.Source = "history"
+  }
+  if result.ToRevision == 0 {
+    result.ToRevision = previousDeployed(deployed, current.Number)
+    result.Source = "deployment"
+  }
+  if result.ToRevision == 0 {
+    return nil, fmt.Errorf("no record of the revision of %s deployed in %s before revision %d; use RollbackTo to name it", assetName, env, current.Number)
+  }
+  if result.ToRevision == current.Number {
+    return nil, fmt.Errorf("revision %d of %s is already deployed in %s", current.Number, assetName, env)
+  }
+
+  _, _, e = s.Deploy(client, uriPathElement, assetName, result.BasePath, env, result.ToRevision)
+  if e != nil {
+    return result, e
+  }
+  e = s.waitForDeployment(client, uriPathElement, assetName, env, result.ToRevision)
+  if e != nil {
+    return result, e
+  }
+  if client.history != nil && position >= 0 {
+    client.history.truncate(uriPathElement, assetName, env, position)
+  }
+  return result, s.undeployOthers(client, uriPathElement, assetName, env, result.ToRevision)
+}
+
+// DeploymentHistory returns the revisions of an API Proxy or SharedFlow that
+// have been deployed into the environment through this client, oldest first.
+func (s *Deployable) DeploymentHistory(client *ApigeeClient, uriPathElement, assetName, env string) []Revision {
+  if client.history == nil {
+    return []Revision{}
+  }
+  return client.history.list(uriPathElement, assetName, env)
+}
@@ -0,0 +1,195 @@
+package apigee
+
+import (
+  "fmt"
+  "net/http"
+  "strconv"
+  "strings"
+  "sync"
+  "testing"
+  "time"
+)
+
+func TestDeploymentHistory(t *testing.T) {
+  h := newDeploymentHistory()
+  for _, rev := range []Revision{1, 2, 2, 3} {
+    h.record("apis", "flights", "prod", rev)
+  }
+  h.record("apis", "flights", "test", 9)
+  if got := fmt.Sprintf("%v", h.list("apis", "flights", "prod")); got != "[1 2 3]" {
+    t.Errorf("list: got=%s, expected=[1 2 3]", got)
+  }
+
+  // roll back from 3 to 2, as Rollback does
+  prev, position := h.previous("apis", "flights", "prod", 3)
+  if prev != 2 {
+    t.Errorf("previous: got=%d, expected=2", prev)
+  }
+  h.record("apis", "flights", "prod", prev)
+  h.truncate("apis", "flights", "prod", position)
+  if got := fmt.Sprintf("%v", h.list("apis", "flights", "prod")); got != "[1 2]" {
+    t.Errorf("after rollback: got=%s, expected=[1 2]", got)
+  }
+
+  // a second rollback moves further back
+  if prev, _ = h.previous("apis", "flights", "prod", 2); prev != 1 {
+    t.Errorf("second previous: got=%d, expected=1", prev)
+  }
+  if prev, _ = h.previous("sharedflows", "flights", "prod", 2); prev != 0 {
+    t.Errorf("unknown asset: got=%d, expected=0", prev)
+  }
+}
+
+func TestPreviousDeployed(t *testing.T) {
+  testCases := []struct {
+    revisions []RevisionDeployment
+    current   Revision
+    expected  Revision
+  }{
+    {[]RevisionDeployment{{Number: 3, State: "deployed"}, {Number: 4, State: "deployed"}}, 4, 3},
+    {[]RevisionDeployment{{Number: 2, State: "deployed"}, {Number: 7, State: "deployed"}, {Number: 5, State: "deployed"}}, 7, 5},
+    {[]RevisionDeployment{{Number: 3, State: "undeployed"}, {Number: 4, State: "deployed"}}, 4, 0},
+    {[]RevisionDeployment{{Number: 4, State: "deployed"}}, 4, 0},
+  }
+  for i, tc := range testCases {
+    if got := previousDeployed(tc.revisions, tc.current); got != tc.expected {
+      t.Errorf("case %d: got=%d, expected=%d", i, got, tc.expected)
+    }
+  }
+}
+
+// deploymentsStub stands in for Edge for the deployments of one API Proxy,
+// flights. Deploys and undeploys change the revisions it reports as deployed.
+type deploymentsStub struct {
+  mu        sync.Mutex
+  // env => revisions deployed there
+  deployed  map[string][]Revision
+  // revisions that fail to deploy
+  failing   map[Revision]bool
+  // the deploys and undeploys requested, like "deploy 2 prod"
+  changes   []string
+}
+
+func (d *deploymentsStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+  d.mu.Lock()
+  defer d.mu.Unlock()
+  p := strings.TrimPrefix(r.URL.Path, "/v1/o/org1/apis/flights/")
+  if r.Method == "GET" && p == "deployments" {
+    envs := []string{}
+    for env, revs := range d.deployed {
+      revisions := []string{}
+      for _, rev := range revs {
+        revisions = append(revisions, fmt.Sprintf(`{"name":"%d","state":"deployed","server":[{"status":"deployed"}],"configuration":{"basePath":"/"}}`, rev))
+      }
+      envs = append(envs, fmt.Sprintf(`{"name":%q,"revision":[%s]}`, env, strings.Join(revisions, ",")))
+    }
+    fmt.Fprintf(w, `{"name":"flights","environment":[%s]}`, strings.Join(envs, ","))
+    return
+  }
+  parts := strings.Split(p, "/")
+  if r.Method != "POST" || len(parts) != 3 || parts[0] != "revisions" || parts[2] != "deployments" {
+    http.Error(w, `{"code":"unexpected"}`, http.StatusBadRequest)
+    return
+  }
+  n, _ := strconv.Atoi(parts[1])
+  rev, action, env := Revision(n), r.URL.Query().Get("action"), r.URL.Query().Get("env")
+  d.changes = append(d.changes, fmt.Sprintf("%s %d %s", action, rev, env))
+  if action == "deploy" {
+    if d.failing[rev] {
+      http.Error(w, `{"code":"distribution.RevisionDeploymentFailed"}`, http.StatusInternalServerError)
+      return
+    }
+    for _, deployed := range d.deployed[env] {
+      if deployed == rev {
+        fmt.Fprintf(w, `{"name":"%d","state":"deployed"}`, rev)
+        return
+      }
+    }
+    d.deployed[env] = append(d.deployed[env], rev)
+  } else {
+    kept := []Revision{}
+    for _, deployed := range d.deployed[env] {
+      if deployed != rev {
+        kept = append(kept, deployed)
+      }
+    }
+    d.deployed[env] = kept
+  }
+  fmt.Fprintf(w, `{"name":"%d","state":"deployed"}`, rev)
+}
+
+func TestRollback(t *testing.T) {
+  saved := DeploymentPollInterval
+  DeploymentPollInterval = time.Millisecond
+  defer func() { DeploymentPollInterval = saved }()
+
+  testCases := []struct {
+    desc      string
+    deployed  []Revision
+    // revisions deployed through the client before the rollback
+    history   []Revision
+    // the revision passed to RollbackTo, or 0 to call Rollback
+    to        Revision
+    expected  *RollbackResult
+    changes   string
+  }{
+    {"history", nil, []Revision{1, 2}, 0,
+      &RollbackResult{FromRevision: 2, ToRevision: 1, Source: "history"},
+      "deploy 1 prod, undeploy 2 prod"},
+    {"still deployed", []Revision{2, 3}, nil, 0,
+      &RollbackResult{FromRevision: 3, ToRevision: 2, Source: "deployment"},
+      "deploy 2 prod, undeploy 3 prod"},
+    {"no record", []Revision{3}, nil, 0, nil, ""},
+    {"named", []Revision{3}, nil, 1,
+      &RollbackResult{FromRevision: 3, ToRevision: 1, Source: "caller"},
+      "deploy 1 prod, undeploy 3 prod"},
+  }
+  for _, tc := range testCases {
+    stub := &deploymentsStub{deployed: map[string][]Revision{"prod": tc.deployed}}
+    client, stop := newStubClient(t, stub.ServeHTTP)
+    for _, rev := range tc.history {
+      if _, _, e := client.Proxies.Deploy("flights", "prod", rev); e != nil {
+        t.Fatalf("%s: while deploying, error: %v", tc.desc, e)
+      }
+      stub.deployed["prod"] = []Revision{rev}
+    }
+    stub.changes = nil
+    var result *RollbackResult
+    var e error
+    if tc.to == 0 {
+      result, e = client.Proxies.Rollback("flights", "prod")
+    } else {
+      result, e = client.Proxies.RollbackTo("flights", "prod", tc.to)
+    }
+    stop()
+    changes := strings.Join(stub.changes, ", ")
+    if tc.expected == nil {
+      if e == nil {
+        t.Errorf("%s: expected an error, got=%#v", tc.desc, result)
+      }
+      if changes != "" {
+        t.Errorf("%s: changes got=%q, expected none", tc.desc, changes)
+      }
+      continue
+    }
+    if e != nil {
+      t.Errorf("%s: unexpected error: %v", tc.desc, e)
+      continue
+    }
+    if result.FromRevision != tc.expected.FromRevision || result.ToRevision != tc.expected.ToRevision || result.Source != tc.expected.Source {
+      t.Errorf("%s: got=%#v, expected=%#v", tc.desc, result, tc.expected)
+    }
+    if changes != tc.changes {
+      t.Errorf("%s: changes got=%q, expected=%q", tc.desc, changes, tc.changes)
+    }
+    if got := fmt.Sprintf("%v", stub.deployed["prod"]); got != fmt.Sprintf("[%d]", tc.expected.ToRevision) {
+      t.Errorf("%s: deployed got=%s", tc.desc, got)
+    }
+    if tc.history != nil {
+      // the history ends at the revision rolled back to
+      if got := fmt.Sprintf("%v", client.Proxies.DeploymentHistory("flights", "prod")); got != "[1]" {
+        t.Errorf("%s: history got=%s, expected=[1]", tc.desc, got)
+      }
+    }
+  }
+}
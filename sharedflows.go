@@ -0,0 +1,191 @@
+package apigee
+
+import (
+  "io"
+)
+
+// SharedFlowsService is an interface for interfacing with the Apigee Admin API
+// dealing with sharedflows.
+type SharedFlowsService interface {
+  List() ([]string, *Response, error)
+  Get(string) (*DeployableAsset, *Response, error)
+  Import(string, string) (*DeployableRevision, *Response, error)
+  ImportIfChanged(string, string) (*DeployableRevision, *Response, error)
+  Delete(string) (*DeletedItemInfo, *Response, error)
+  DeleteRevision(string, Revision) (*DeployableRevision, *Response, error)
+  Deploy(string,string,Revision) (*RevisionDeployment, *Response, error)
+  Undeploy(string,string,Revision) (*RevisionDeployment, *Response, error)
+  Export(string, Revision) (string, *Response, error)
+  GetDeployments(string) (*Deployment, *Response, error)
+  PruneRevisions(string, PruneOptions) (*PruneReport, error)
+  PruneAll(PruneOptions) ([]PruneReport, error)
+  Promote(string, string, string) (*PromoteResult, error)
+  Rollback(string, string) (*RollbackResult, error)
+  RollbackTo(string, string, Revision) (*RollbackResult, error)
+  DeploymentHistory(string, string) []Revision
+  GetRevision(string, Revision) (*DeployableRevision, *Response, error)
+  ListPolicies(string, Revision) ([]string, *Response, error)
+  GetPolicy(string, Revision, string) (string, *Response, error)
+  ListResourceFiles(string, Revision) ([]ResourceFile, *Response, error)
+  GetResourceFile(string, Revision, string, string) ([]byte, *Response, error)
+  CreateResourceFile(string, Revision, string, string, io.Reader) (*ResourceFile, *Response, error)
+  UpdateResourceFile(string, Revision, string, string, io.Reader) (*ResourceFile, *Response, error)
+  DeleteResourceFile(string, Revision, string, string) (*ResourceFile, *Response, error)
+}
+
+type SharedFlowsServiceOp struct {
+  client *ApigeeClient
+  deployable Deployable
+}
+
+var _ SharedFlowsService = &SharedFlowsServiceOp{}
+
+// retrieve the list of sharedflow names for the organization referred by the ApigeeClient.
+func (s *SharedFlowsServiceOp) List() ([]string, *Response, error) {
+	return s.deployable.List(s.client, sfUriPathElement)
+}
+
+// Get retrieves the information about a SharedFlow in an organization, information including
+// the list of available revisions, and the created and last modified dates and actors.
+func (s *SharedFlowsServiceOp) Get(sharedFlowName string) (*DeployableAsset, *Response, error) {
+	return s.deployable.Get(s.client, sfUriPathElement, sharedFlowName)
+}
+
+// Import a SharedFlow into an organization, creating a new SharedFlow revision.
+// The sharedFlowName can be passed as "nil" in which case the name is derived from the source.
+// The source can be either a filesystem directory containing an exploded sharedflowbundle, OR
+// the path of a zip file containing a SharedFlow bundle. Returns the SharedFlow revision information.
+// This method does not deploy the imported SharedFlow. See the Deploy method.
+func (s *SharedFlowsServiceOp) Import(sharedFlowName string, source string) (*DeployableRevision, *Response, error) {
+	return s.deployable.Import(s.client, sfUriPathElement, sharedFlowName, source)
+}
+
+// ImportIfChanged imports a SharedFlow into an organization, creating a new
+// SharedFlow revision, only if the bundle content differs from the latest existing
+// revision. The comparison uses the fingerprint computed by BundleFingerprint.
+// When the content is unchanged, nothing is uploaded, and the returned revision
+// information describes the existing latest revision.
+func (s *SharedFlowsServiceOp) ImportIfChanged(sharedFlowName string, source string) (*DeployableRevision, *Response, error) {
+	return s.deployable.ImportIfChanged(s.client, sfUriPathElement, sharedFlowName, source)
+}
+
+// Export a revision of a SharedFlow within an organization, to a filesystem file.
+func (s *SharedFlowsServiceOp) Export(sharedFlowName string, rev Revision) (string, *Response, error) {
+	return s.deployable.Export(s.client, sfUriPathElement, sharedFlowName, rev)
+}
+
+// DeleteRevision deletes a specific revision of a SharedFlow from an organization.
+// The revision must exist, and must not be currently deployed.
+func (s *SharedFlowsServiceOp) DeleteRevision(sharedFlowName string, rev Revision) (*DeployableRevision, *Response, error) {
+	return s.deployable.DeleteRevision(s.client, sfUriPathElement, sharedFlowName, rev)
+}
+
+// Undeploy a specific revision of a SharedFlow from a particular environment within an Edge organization.
+func (s *SharedFlowsServiceOp) Undeploy(sharedFlowName, env string, rev Revision) (*RevisionDeployment, *Response, error) {
+	return s.deployable.Undeploy(s.client, sfUriPathElement, sharedFlowName, env, rev)
+}
+
+// Deploy a revision of a SharedFlow to a specific environment within an organization.
+func (s *SharedFlowsServiceOp) Deploy(sharedFlowName, env string, rev Revision) (*RevisionDeployment, *Response, error) {
+	return s.deployable.Deploy(s.client, sfUriPathElement, sharedFlowName, "", env, rev)
+}
+
+// Delete a SharedFlow and all its revisions from an organization. This method
+// will fail if any of the revisions of the named SharedFlow are currently deployed
+// in any environment.
+func (s *SharedFlowsServiceOp) Delete(sharedFlowName string) (*DeletedItemInfo, *Response, error) {
+	return s.deployable.Delete(s.client, sfUriPathElement, sharedFlowName)
+}
+
+// GetDeployments retrieves the information about deployments of a SharedFlow in
+// an organization, including the environment names and revision numbers.
+func (s *SharedFlowsServiceOp) GetDeployments(sharedFlowName string) (*Deployment, *Response, error) {
+	return s.deployable.GetDeployments(s.client, sfUriPathElement, sharedFlowName)
+}
+
+// PruneRevisions deletes old revisions of a SharedFlow. It keeps the newest
+// opts.KeepLatest revisions, and every revision currently deployed in any
+// environment. With opts.DryRun, it reports what it would delete without deleting.
+func (s *SharedFlowsServiceOp) PruneRevisions(sharedFlowName string, opts PruneOptions) (*PruneReport, error) {
+	return s.deployable.PruneRevisions(s.client, sfUriPathElement, sharedFlowName, opts)
+}
+
+// PruneAll applies PruneRevisions to every SharedFlow in the organization,
+// returning one report per SharedFlow.
+func (s *SharedFlowsServiceOp) PruneAll(opts PruneOptions) ([]PruneReport, error) {
+	return s.deployable.PruneAll(s.client, sfUriPathElement, opts)
+}
+
+// Promote deploys into toEnv the revision of a SharedFlow that is currently
+// deployed in fromEnv, waits for the deployment to complete on all servers, and
+// then undeploys the revision previously deployed in toEnv. If the new
+// deployment fails, the previous revision is redeployed.
+func (s *SharedFlowsServiceOp) Promote(sharedFlowName, fromEnv, toEnv string) (*PromoteResult, error) {
+	return s.deployable.Promote(s.client, sfUriPathElement, sharedFlowName, fromEnv, toEnv)
+}
+
+// Rollback redeploys into env the revision of a SharedFlow that was deployed
+// before the current one, with override, waits for it to be deployed on all
+// servers, and undeploys the current revision. The previous revision comes from
+// the deployments made through this client, if any, else from another revision
+// still deployed in env. If there is neither, Rollback returns an error; use
+// RollbackTo to name the revision.
+func (s *SharedFlowsServiceOp) Rollback(sharedFlowName, env string) (*RollbackResult, error) {
+	return s.deployable.Rollback(s.client, sfUriPathElement, sharedFlowName, env)
+}
+
+// RollbackTo is like Rollback, but redeploys the named revision.
+func (s *SharedFlowsServiceOp) RollbackTo(sharedFlowName, env string, rev Revision) (*RollbackResult, error) {
+	return s.deployable.RollbackTo(s.client, sfUriPathElement, sharedFlowName, env, rev)
+}
+
+// DeploymentHistory returns the revisions of a SharedFlow deployed into env
+// through this client, oldest first.
+func (s *SharedFlowsServiceOp) DeploymentHistory(sharedFlowName, env string) []Revision {
+	return s.deployable.DeploymentHistory(s.client, sfUriPathElement, sharedFlowName, env)
+}
+
+// GetRevision retrieves the information about a specific revision of a SharedFlow,
+// including the names of its policies, resources, and endpoints.
+func (s *SharedFlowsServiceOp) GetRevision(sharedFlowName string, rev Revision) (*DeployableRevision, *Response, error) {
+	return s.deployable.GetRevision(s.client, sfUriPathElement, sharedFlowName, rev)
+}
+
+// ListPolicies retrieves the names of the policies in a revision of a SharedFlow.
+func (s *SharedFlowsServiceOp) ListPolicies(sharedFlowName string, rev Revision) ([]string, *Response, error) {
+	return s.deployable.ListPolicies(s.client, sfUriPathElement, sharedFlowName, rev)
+}
+
+// GetPolicy retrieves the XML configuration of a policy in a revision of a SharedFlow.
+func (s *SharedFlowsServiceOp) GetPolicy(sharedFlowName string, rev Revision, policyName string) (string, *Response, error) {
+	return s.deployable.GetPolicy(s.client, sfUriPathElement, sharedFlowName, rev, policyName)
+}
+
+// ListResourceFiles retrieves the names and types of the resource files in a
+// revision of a SharedFlow.
+func (s *SharedFlowsServiceOp) ListResourceFiles(sharedFlowName string, rev Revision) ([]ResourceFile, *Response, error) {
+	return s.deployable.ListResourceFiles(s.client, sfUriPathElement, sharedFlowName, rev)
+}
+
+// GetResourceFile retrieves the content of a resource file in a revision of a SharedFlow.
+// The resourceType is one of jsc, java, py, node, xsl, wsdl, xsd, and so on.
+func (s *SharedFlowsServiceOp) GetResourceFile(sharedFlowName string, rev Revision, resourceType, resourceName string) ([]byte, *Response, error) {
+	return s.deployable.GetResourceFile(s.client, sfUriPathElement, sharedFlowName, rev, resourceType, resourceName)
+}
+
+// CreateResourceFile adds a resource file to a revision of a SharedFlow.
+func (s *SharedFlowsServiceOp) CreateResourceFile(sharedFlowName string, rev Revision, resourceType, resourceName string, content io.Reader) (*ResourceFile, *Response, error) {
+	return s.deployable.CreateResourceFile(s.client, sfUriPathElement, sharedFlowName, rev, resourceType, resourceName, content)
+}
+
+// UpdateResourceFile replaces the content of a resource file in a revision of
+// a SharedFlow. This allows a small fix, like a change to one JavaScript file,
+// without exporting and re-importing the bundle.
+func (s *SharedFlowsServiceOp) UpdateResourceFile(sharedFlowName string, rev Revision, resourceType, resourceName string, content io.Reader) (*ResourceFile, *Response, error) {
+	return s.deployable.UpdateResourceFile(s.client, sfUriPathElement, sharedFlowName, rev, resourceType, resourceName, content)
+}
+
+// DeleteResourceFile removes a resource file from a revision of a SharedFlow.
+func (s *SharedFlowsServiceOp) DeleteResourceFile(sharedFlowName string, rev Revision, resourceType, resourceName string) (*ResourceFile, *Response, error) {
+	return s.deployable.DeleteResourceFile(s.client, sfUriPathElement, sharedFlowName, rev, resourceType, resourceName)
+}
@@ -0,0 +1,51 @@
+package apigee
+
+import (
+  "encoding/json"
+)
+
+// SSLInfo holds the TLS settings of a TargetServer or a VirtualHost.
+type SSLInfo struct {
+  Enabled                bool      `json:"enabled"`
+  ClientAuthEnabled      bool      `json:"clientAuthEnabled"`
+  KeyStore               string    `json:"keyStore,omitempty"`
+  KeyAlias               string    `json:"keyAlias,omitempty"`
+  TrustStore             string    `json:"trustStore,omitempty"`
+  IgnoreValidationErrors bool      `json:"ignoreValidationErrors"`
+  Ciphers                []string  `json:"ciphers,omitempty"`
+  Protocols              []string  `json:"protocols,omitempty"`
+}
+
+type sslInfoAlias SSLInfo
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Edge returns the
+// boolean settings of SSLInfo as strings, like "true". This accepts either
+// strings or booleans.
+func (info *SSLInfo) UnmarshalJSON(b []byte) error {
+  var m1 map[string]json.RawMessage
+  e := json.Unmarshal(b, &m1)
+  if e != nil {
+    return e
+  }
+  for _, k := range []string{"enabled", "clientAuthEnabled", "ignoreValidationErrors"} {
+    if v, ok := m1[k]; ok {
+      switch string(v) {
+        case `"true"`:
+          m1[k] = json.RawMessage("true")
+        case `"false"`, `""`:
+          m1[k] = json.RawMessage("false")
+      }
+    }
+  }
+  normalized, e := json.Marshal(m1)
+  if e != nil {
+    return e
+  }
+  alias := sslInfoAlias{}
+  e = json.Unmarshal(normalized, &alias)
+  if e != nil {
+    return e
+  }
+  *info = SSLInfo(alias)
+  return nil
+}
@@ -0,0 +1,152 @@
+package apigee
+
+import (
+  "path"
+  "errors"
+  "fmt"
+)
+
+const targetServersPath = "targetservers"
+
+// TargetServersService is an interface for interfacing with the Apigee Edge Admin API
+// dealing with TargetServers in an environment.
+type TargetServersService interface {
+  List(string) ([]string, *Response, error)
+  Get(string, string) (*TargetServer, *Response, error)
+  Create(TargetServer, string) (*TargetServer, *Response, error)
+  Update(TargetServer, string) (*TargetServer, *Response, error)
+  Delete(string, string) (*TargetServer, *Response, error)
+  Copy(string, string) ([]string, error)
+}
+
+type TargetServersServiceOp struct {
+  client *ApigeeClient
+}
+
+var _ TargetServersService = &TargetServersServiceOp{}
+
+// TargetServer contains information about a TargetServer within an Edge environment.
+type TargetServer struct {
+  Name       string     `json:"name,omitempty"`
+  Host       string     `json:"host,omitempty"`
+  Port       int        `json:"port,omitempty"`
+  IsEnabled  bool       `json:"isEnabled"`
+  SSLInfo    *SSLInfo   `json:"sSLInfo,omitempty"`
+}
+
+func targetServerPath(env string, elements ...string) string {
+  return path.Join(append([]string{"e", env, targetServersPath}, elements...)...)
+}
+
+// List retrieves the list of TargetServer names in an environment.
+func (s *TargetServersServiceOp) List(env string) ([]string, *Response, error) {
+  req, e := s.client.NewRequest("GET", targetServerPath(env), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  namelist := make([]string,0)
+  resp, e := s.client.Do(req, &namelist)
+  if e != nil {
+    return nil, resp, e
+  }
+  return namelist, resp, e
+}
+
+// Get retrieves the information about a TargetServer in an environment,
+// including the host, port, and TLS settings.
+func (s *TargetServersServiceOp) Get(name, env string) (*TargetServer, *Response, error) {
+  req, e := s.client.NewRequest("GET", targetServerPath(env, name), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedServer := TargetServer{}
+  resp, e := s.client.Do(req, &returnedServer)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedServer, resp, e
+}
+
+// Create adds a TargetServer to an environment.
+func (s *TargetServersServiceOp) Create(server TargetServer, env string) (*TargetServer, *Response, error) {
+  if server.Name == "" || server.Host == "" {
+    return nil, nil, errors.New("must specify the Name and Host of the TargetServer to create")
+  }
+  req, e := s.client.NewRequest("POST", targetServerPath(env), server)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedServer := TargetServer{}
+  resp, e := s.client.Do(req, &returnedServer)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedServer, resp, e
+}
+
+// Update replaces the definition of an existing TargetServer in an environment.
+func (s *TargetServersServiceOp) Update(server TargetServer, env string) (*TargetServer, *Response, error) {
+  if server.Name == "" {
+    return nil, nil, errors.New("must specify the Name of the TargetServer to update")
+  }
+  req, e := s.client.NewRequest("PUT", targetServerPath(env, server.Name), server)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedServer := TargetServer{}
+  resp, e := s.client.Do(req, &returnedServer)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedServer, resp, e
+}
+
+// Delete removes a TargetServer from an environment.
+func (s *TargetServersServiceOp) Delete(name, env string) (*TargetServer, *Response, error) {
+  req, e := s.client.NewRequest("DELETE", targetServerPath(env, name), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  deletedServer := TargetServer{}
+  resp, e := s.client.Do(req, &deletedServer)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &deletedServer, resp, e
+}
+
+// Copy creates or updates, in the environment toEnv, each of the TargetServers
+// defined in the environment fromEnv. It returns the names of the TargetServers
+// copied.
+func (s *TargetServersServiceOp) Copy(fromEnv, toEnv string) ([]string, error) {
+  names, _, e := s.List(fromEnv)
+  if e != nil {
+    return nil, e
+  }
+  existing, _, e := s.List(toEnv)
+  if e != nil {
+    return nil, e
+  }
+  exists := map[string]bool{}
+  for _, name := range existing {
+    exists[name] = true
+  }
+
+  copied := []string{}
+  for _, name := range names {
+    server, _, e := s.Get(name, fromEnv)
+    if e != nil {
+      return copied, e
+    }
+    if exists[name] {
+      _, _, e = s.Update(*server, toEnv)
+    } else {
+      _, _, e = s.Create(*server, toEnv)
+    }
+    if e != nil {
+      return copied, fmt.Errorf("while copying TargetServer %s to %s: %v", name, toEnv, e)
+    }
+    copied = append(copied, name)
+  }
+  return copied, nil
+}
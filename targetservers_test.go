@@ -0,0 +1,81 @@
+package apigee
+
+import (
+  "encoding/json"
+  "testing"
+)
+
+const (
+  targetServerJson1 = `{
+  "host" : "backend.example.com",
+  "isEnabled" : true,
+  "name" : "backend-1",
+  "port" : 443,
+  "sSLInfo" : {
+    "ciphers" : [ ],
+    "clientAuthEnabled" : "true",
+    "enabled" : "true",
+    "ignoreValidationErrors" : false,
+    "keyAlias" : "client",
+    "keyStore" : "ks1",
+    "protocols" : [ "TLSv1.2" ],
+    "trustStore" : "ts1"
+  }
+}`
+  targetServerJson2 = `{
+  "host" : "10.10.1.4",
+  "isEnabled" : false,
+  "name" : "backend-2",
+  "port" : 8080
+}`
+)
+
+func TestTargetServer_Unmarshal(t *testing.T) {
+  testCases := []struct {
+    desc     string
+    data     string
+    expected TargetServer
+  }{
+    {"with TLS", targetServerJson1, TargetServer{Name: "backend-1", Host: "backend.example.com", Port: 443, IsEnabled: true,
+      SSLInfo: &SSLInfo{Enabled: true, ClientAuthEnabled: true, KeyStore: "ks1", KeyAlias: "client", TrustStore: "ts1", Ciphers: []string{}, Protocols: []string{"TLSv1.2"}}}},
+    {"no TLS", targetServerJson2, TargetServer{Name: "backend-2", Host: "10.10.1.4", Port: 8080}},
+  }
+  for _, tc := range testCases {
+    var got TargetServer
+    e := json.Unmarshal([]byte(tc.data), &got)
+    if e != nil {
+      t.Errorf("%s: error=%v", tc.desc, e)
+      continue
+    }
+    gotJson, _ := json.Marshal(got)
+    expectedJson, _ := json.Marshal(tc.expected)
+    if string(gotJson) != string(expectedJson) {
+      t.Errorf("%s: got=%s, expected=%s", tc.desc, gotJson, expectedJson)
+    }
+  }
+}
+
+func TestSSLInfo_UnmarshalBooleans(t *testing.T) {
+  testCases := []struct {
+    data     string
+    expected bool
+    wantErr  bool
+  }{
+    {`{"enabled":"true"}`, true, false},
+    {`{"enabled":true}`, true, false},
+    {`{"enabled":"false"}`, false, false},
+    {`{"enabled":false}`, false, false},
+    {`{"enabled":"maybe"}`, false, true},
+  }
+  for _, tc := range testCases {
+    var got SSLInfo
+    e := json.Unmarshal([]byte(tc.data), &got)
+    if gotErr := e != nil; gotErr != tc.wantErr {
+      t.Errorf("%s: gotErr=%v, wantErr=%v, err=%v", tc.data, gotErr, tc.wantErr, e)
+      continue
+    }
+    if got.Enabled != tc.expected {
+      t.Errorf("%s: got=%v, expected=%v", tc.data, got.Enabled, tc.expected)
+    }
+  }
+}
@@ -0,0 +1,150 @@
+package apigee
+
+import (
+  "path"
+  "errors"
+)
+
+const virtualHostsPath = "virtualhosts"
+
+// VirtualHostsService is an interface for interfacing with the Apigee Edge Admin API
+// dealing with virtual hosts in an environment.
+type VirtualHostsService interface {
+  List(string) ([]string, *Response, error)
+  Get(string, string) (*VirtualHost, *Response, error)
+  Create(VirtualHost, string) (*VirtualHost, *Response, error)
+  Update(VirtualHost, string) (*VirtualHost, *Response, error)
+  Delete(string, string) (*VirtualHost, *Response, error)
+  CheckProxy(string, Revision, string) ([]string, error)
+}
+
+type VirtualHostsServiceOp struct {
+  client *ApigeeClient
+}
+
+var _ VirtualHostsService = &VirtualHostsServiceOp{}
+
+// VirtualHost contains information about a virtual host within an Edge
+// environment. Properties holds settings like proxy_read_timeout. The KeyStore
+// in SSLInfo can name a keystore, or a reference to one, like "ref://myref".
+type VirtualHost struct {
+  Name         string           `json:"name,omitempty"`
+  HostAliases  []string         `json:"hostAliases,omitempty"`
+  Interfaces   []string         `json:"interfaces,omitempty"`
+  Port         int              `json:"port,string,omitempty"`
+  BaseUrl      string           `json:"baseUrl,omitempty"`
+  SSLInfo      *SSLInfo         `json:"sSLInfo,omitempty"`
+  Properties   PropertyWrapper  `json:"properties,omitempty"`
+}
+
+func virtualHostPath(env string, elements ...string) string {
+  return path.Join(append([]string{"e", env, virtualHostsPath}, elements...)...)
+}
+
+// List retrieves the list of virtual host names in an environment.
+func (s *VirtualHostsServiceOp) List(env string) ([]string, *Response, error) {
+  req, e := s.client.NewRequest("GET", virtualHostPath(env), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  namelist := make([]string,0)
+  resp, e := s.client.Do(req, &namelist)
+  if e != nil {
+    return nil, resp, e
+  }
+  return namelist, resp, e
+}
+
+// Get retrieves the information about a virtual host in an environment,
+// including the host aliases, port, and TLS settings.
+func (s *VirtualHostsServiceOp) Get(name, env string) (*VirtualHost, *Response, error) {
+  req, e := s.client.NewRequest("GET", virtualHostPath(env, name), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedHost := VirtualHost{}
+  resp, e := s.client.Do(req, &returnedHost)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedHost, resp, e
+}
+
+// Create adds a virtual host to an environment.
+func (s *VirtualHostsServiceOp) Create(vhost VirtualHost, env string) (*VirtualHost, *Response, error) {
+  if vhost.Name == "" || len(vhost.HostAliases) == 0 {
+    return nil, nil, errors.New("must specify the Name and at least one HostAlias of the virtual host to create")
+  }
+  req, e := s.client.NewRequest("POST", virtualHostPath(env), vhost)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedHost := VirtualHost{}
+  resp, e := s.client.Do(req, &returnedHost)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedHost, resp, e
+}
+
+// Update replaces the definition of an existing virtual host in an environment.
+func (s *VirtualHostsServiceOp) Update(vhost VirtualHost, env string) (*VirtualHost, *Response, error) {
+  if vhost.Name == "" {
+    return nil, nil, errors.New("must specify the Name of the virtual host to update")
+  }
+  req, e := s.client.NewRequest("PUT", virtualHostPath(env, vhost.Name), vhost)
+  if e != nil {
+    return nil, nil, e
+  }
+  returnedHost := VirtualHost{}
+  resp, e := s.client.Do(req, &returnedHost)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &returnedHost, resp, e
+}
+
+// Delete removes a virtual host from an environment.
+func (s *VirtualHostsServiceOp) Delete(name, env string) (*VirtualHost, *Response, error) {
+  req, e := s.client.NewRequest("DELETE", virtualHostPath(env, name), nil)
+  if e != nil {
+    return nil, nil, e
+  }
+  deletedHost := VirtualHost{}
+  resp, e := s.client.Do(req, &deletedHost)
+  if e != nil {
+    return nil, resp, e
+  }
+  return &deletedHost, resp, e
+}
+
+// CheckProxy examines the ProxyEndpoints of a revision of an API Proxy, and
+// returns the names of the virtual hosts they reference that do not exist in
+// the environment. An empty result means the revision can be deployed there,
+// as far as virtual hosts are concerned.
+func (s *VirtualHostsServiceOp) CheckProxy(proxyName string, rev Revision, env string) ([]string, error) {
+  existing, _, e := s.List(env)
+  if e != nil {
+    return nil, e
+  }
+  defined := map[string]bool{}
+  for _, name := range existing {
+    defined[name] = true
+  }
+
+  connections, e := proxyConnections(s.client, proxyName, rev)
+  if e != nil {
+    return nil, e
+  }
+  missing := []string{}
+  seen := map[string]bool{}
+  for _, connection := range connections {
+    for _, vhost := range connection.VirtualHosts {
+      if !defined[vhost] && !seen[vhost] {
+        missing = append(missing, vhost)
+        seen[vhost] = true
+      }
+    }
+  }
+  return missing, nil
+}
@@ -0,0 +1,90 @@
+package apigee
+
+import (
+  "encoding/json"
+  "fmt"
+  "testing"
+)
+
+const (
+  virtualHostJson1 = `{
+  "hostAliases" : [ "api.example.com", "api2.example.com" ],
+  "interfaces" : [ ],
+  "listenOptions" : [ ],
+  "name" : "secure",
+  "port" : "443",
+  "baseUrl" : "https://api.example.com",
+  "properties" : {
+    "property" : [ {
+      "name" : "proxy_read_timeout",
+      "value" : "50"
+    } ]
+  },
+  "retryOptions" : [ ],
+  "sSLInfo" : {
+    "ciphers" : [ ],
+    "clientAuthEnabled" : "false",
+    "enabled" : "true",
+    "ignoreValidationErrors" : false,
+    "keyAlias" : "api-cert",
+    "keyStore" : "ref://api-keystore-ref",
+    "protocols" : [ ]
+  }
+}`
+
+  proxyEndpointXml1 = `<ProxyEndpoint name="endpoint1">
+  <Description>Proxy for flights</Description>
+  <HTTPProxyConnection>
+    <BasePath>
+      /v1/flights
+    </BasePath>
+    <Properties/>
+    <VirtualHost>default</VirtualHost>
+    <VirtualHost>secure</VirtualHost>
+  </HTTPProxyConnection>
+  <RouteRule name="default">
+    <TargetEndpoint>target-1</TargetEndpoint>
+  </RouteRule>
+</ProxyEndpoint>`
+)
+
+func TestVirtualHost_Unmarshal(t *testing.T) {
+  var got VirtualHost
+  e := json.Unmarshal([]byte(virtualHostJson1), &got)
+  if e != nil {
+    t.Errorf("while unmarshaling, error:\n%#v\n", e)
+    return
+  }
+  if got.Name != "secure" || got.Port != 443 || len(got.HostAliases) != 2 || got.BaseUrl != "https://api.example.com" {
+    t.Errorf("got=%#v", got)
+  }
+  if got.Properties.Property["proxy_read_timeout"] != "50" {
+    t.Errorf("properties: got=%v", got.Properties.Property)
+  }
+  if got.SSLInfo == nil || !got.SSLInfo.Enabled || got.SSLInfo.ClientAuthEnabled || got.SSLInfo.KeyStore != "ref://api-keystore-ref" {
+    t.Errorf("sSLInfo: got=%#v", got.SSLInfo)
+  }
+
+  out, e := json.Marshal(got)
+  if e != nil {
+    t.Errorf("while marshaling, error:\n%#v\n", e)
+    return
+  }
+  var roundTrip VirtualHost
+  e = json.Unmarshal(out, &roundTrip)
+  if e != nil || roundTrip.Port != 443 {
+    t.Errorf("round trip: got=%#v, err=%v", roundTrip, e)
+  }
+}
+
+func TestProxyEndpointConnection_Parse(t *testing.T) {
+  got, e := parseProxyEndpointConnection(proxyEndpointXml1)
+  if e != nil {
+    t.Errorf("while parsing, error:\n%#v\n", e)
+    return
+  }
+  expected := "endpoint1 /v1/flights [default secure]"
+  if s := fmt.Sprintf("%s %s %v", got.Endpoint, got.BasePath, got.VirtualHosts); s != expected {
+    t.Errorf("got=%s, expected=%s", s, expected)
+  }
+}